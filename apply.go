@@ -0,0 +1,317 @@
+package helmclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const defaultApplyConcurrency = 4
+
+// ApplyReleases installs or upgrades the provided releases in the order of their dependencies, see ChartSpec.DependsOn.
+// Releases whose dependencies are deployed are applied in parallel, bounded by the configured concurrency.
+// A release is only applied once all of its dependencies have been installed or upgraded successfully,
+// so dependencies should set ChartSpec.Wait if their resources are required to be ready.
+// Releases without a namespace are applied to the namespace of the client, releases of other namespaces
+// via a client derived with WithNamespace. Releases using GenerateName cannot be referenced as a dependency.
+// The results are returned in the order of the provided specs, along with the joined errors of all failed releases.
+// Invalid dependencies, e.g. cycles, and, if enabled via GenericHelmOptions.ValidateSpec, invalid specs
+// are reported before any release is applied.
+func (c *HelmClient) ApplyReleases(ctx context.Context, specs []*ChartSpec, opts *ApplyReleasesOptions) ([]*ReleaseApplyResult, error) {
+	if opts == nil {
+		opts = &ApplyReleasesOptions{}
+	}
+
+	concurrency := defaultApplyConcurrency
+	if opts.Concurrency > 0 {
+		concurrency = opts.Concurrency
+	}
+
+	if opts.HelmOptions != nil && opts.HelmOptions.ValidateSpec {
+		for _, spec := range specs {
+			if err := validateSpec(spec); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	specs = c.resolveNamespaces(specs)
+
+	graph, err := newReleaseGraph(specs)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := make([]string, len(specs))
+	for i, spec := range specs {
+		namespaces[i] = spec.Namespace
+	}
+
+	clients, err := c.namespaceClients(namespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*ReleaseApplyResult, len(specs))
+	pending := make([]int, len(specs))
+
+	var ready []int
+	for i := range specs {
+		pending[i] = len(graph.dependencies[i])
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	// skip marks the release and all releases depending on it as skipped.
+	var skip func(i int, err error)
+	skip = func(i int, err error) {
+		if results[i] != nil {
+			return
+		}
+
+		results[i] = &ReleaseApplyResult{ReleaseName: specs[i].ReleaseName, Namespace: specs[i].Namespace, Err: err, Skipped: true}
+		for _, dependent := range graph.dependents[i] {
+			skip(dependent, fmt.Errorf("%w: %q", ErrReleaseDependencyFailed, specs[i].ReleaseName))
+		}
+	}
+
+	done := make(chan int)
+	running := 0
+	stopped := false
+
+	for {
+		for !stopped && ctx.Err() == nil && running < concurrency && len(ready) > 0 {
+			i := ready[0]
+			ready = ready[1:]
+			running++
+
+			go func() {
+				results[i] = applyRelease(ctx, clients[specs[i].Namespace].withOwnActionConfig(), specs[i], opts.HelmOptions)
+				done <- i
+			}()
+		}
+
+		if running == 0 {
+			break
+		}
+
+		i := <-done
+		running--
+
+		if results[i].Err != nil {
+			c.DebugLog("failed to apply release %q: %s", specs[i].ReleaseName, results[i].Err)
+
+			if opts.FailurePolicy != ApplyContinueOnFailure {
+				stopped = true
+			}
+
+			for _, dependent := range graph.dependents[i] {
+				skip(dependent, fmt.Errorf("%w: %q", ErrReleaseDependencyFailed, specs[i].ReleaseName))
+			}
+
+			continue
+		}
+
+		for _, dependent := range graph.dependents[i] {
+			pending[dependent]--
+			if pending[dependent] == 0 && results[dependent] == nil {
+				ready = append(ready, dependent)
+			}
+		}
+
+		// Apply ready releases in the order they were provided in.
+		sort.Ints(ready)
+	}
+
+	var errs []error
+	for i, result := range results {
+		if result == nil {
+			// The release was never started, because applying was stopped or the context was canceled.
+			err := ctx.Err()
+			if err == nil {
+				err = ErrApplyStopped
+			}
+
+			results[i] = &ReleaseApplyResult{ReleaseName: specs[i].ReleaseName, Namespace: specs[i].Namespace, Err: err, Skipped: true}
+			continue
+		}
+
+		if result.Err != nil && !result.Skipped {
+			errs = append(errs, fmt.Errorf("failed to apply release %q: %w", result.ReleaseName, result.Err))
+		}
+	}
+
+	if len(errs) == 0 && ctx.Err() != nil {
+		errs = append(errs, ctx.Err())
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// resolveNamespaces returns copies of the provided specs without a namespace, using the namespace of the client.
+func (c *HelmClient) resolveNamespaces(specs []*ChartSpec) []*ChartSpec {
+	resolved := make([]*ChartSpec, len(specs))
+	for i, spec := range specs {
+		resolved[i] = spec
+		if spec.Namespace == "" {
+			namespaced := *spec
+			namespaced.Namespace = c.Settings.Namespace()
+			resolved[i] = &namespaced
+		}
+	}
+
+	return resolved
+}
+
+// namespaceClients returns the clients for the namespace of this client and the provided namespaces by their namespace.
+func (c *HelmClient) namespaceClients(namespaces []string) (map[string]*HelmClient, error) {
+	clients := map[string]*HelmClient{c.Settings.Namespace(): c}
+	for _, namespace := range namespaces {
+		if _, ok := clients[namespace]; ok {
+			continue
+		}
+
+		namespaced, err := c.WithNamespace(namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create a client for namespace %q: %w", namespace, err)
+		}
+		clients[namespace] = namespaced.(*HelmClient)
+	}
+
+	return clients, nil
+}
+
+// withOwnActionConfig returns a copy of the client using its own copy of the action configuration and release storage.
+// Helm modifies both while installing or upgrading a release, e.g. the history limit and the cached capabilities,
+// so releases applied concurrently must not share them.
+func (c *HelmClient) withOwnActionConfig() *HelmClient {
+	actionConfig := *c.ActionConfig
+	if c.ActionConfig.Releases != nil {
+		releases := *c.ActionConfig.Releases
+		actionConfig.Releases = &releases
+	}
+
+	client := *c
+	client.ActionConfig = &actionConfig
+
+	return &client
+}
+
+// applyRelease installs or upgrades the release of the provided spec using the provided client.
+func applyRelease(ctx context.Context, c Client, spec *ChartSpec, opts *GenericHelmOptions) *ReleaseApplyResult {
+	result := &ReleaseApplyResult{ReleaseName: spec.ReleaseName, Namespace: spec.Namespace}
+
+	start := time.Now()
+	result.Release, result.Err = c.InstallOrUpgradeChart(ctx, spec, opts)
+	result.Duration = time.Since(start)
+
+	return result
+}
+
+// releaseGraph holds the dependencies between a set of releases by their index.
+type releaseGraph struct {
+	specs        []*ChartSpec
+	dependencies [][]int
+	dependents   [][]int
+}
+
+// newReleaseGraph resolves the dependencies of the provided releases and validates that they do not form a cycle.
+// The namespaces of the releases must be resolved. Releases using GenerateName are not indexed, as their name is not known yet.
+func newReleaseGraph(specs []*ChartSpec) (*releaseGraph, error) {
+	index := make(map[string]int, len(specs))
+	for i, spec := range specs {
+		if spec.GenerateName {
+			continue
+		}
+
+		key := spec.Namespace + "/" + spec.ReleaseName
+		if _, ok := index[key]; ok {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateRelease, key)
+		}
+		index[key] = i
+	}
+
+	graph := &releaseGraph{
+		specs:        specs,
+		dependencies: make([][]int, len(specs)),
+		dependents:   make([][]int, len(specs)),
+	}
+
+	for i, spec := range specs {
+		for _, dependency := range spec.DependsOn {
+			key := dependency
+			if !strings.Contains(dependency, "/") {
+				key = spec.Namespace + "/" + dependency
+			}
+
+			j, ok := index[key]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q depends on %q", ErrReleaseDependencyNotFound, spec.ReleaseName, dependency)
+			}
+
+			graph.dependencies[i] = append(graph.dependencies[i], j)
+			graph.dependents[j] = append(graph.dependents[j], i)
+		}
+	}
+
+	if cycle := graph.cycle(); cycle != nil {
+		return nil, fmt.Errorf("%w: %s", ErrReleaseDependencyCycle, strings.Join(cycle, " -> "))
+	}
+
+	return graph, nil
+}
+
+// cycle returns the names of the releases forming a dependency cycle, or nil if there is none.
+func (g *releaseGraph) cycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make([]int, len(g.specs))
+	var path []int
+
+	var visit func(i int) []string
+	visit = func(i int) []string {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			var cycle []string
+			for k := len(path) - 1; k >= 0; k-- {
+				cycle = append([]string{g.specs[path[k]].ReleaseName}, cycle...)
+				if path[k] == i {
+					break
+				}
+			}
+			return append(cycle, g.specs[i].ReleaseName)
+		}
+
+		state[i] = visiting
+		path = append(path, i)
+
+		for _, dependency := range g.dependencies[i] {
+			if cycle := visit(dependency); cycle != nil {
+				return cycle
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[i] = visited
+
+		return nil
+	}
+
+	for i := range g.specs {
+		if cycle := visit(i); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,240 @@
+package helmclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestApplyReleases(t *testing.T) {
+	chartPath := writeTestChart(t, map[string]string{
+		"Chart.yaml":               "apiVersion: v2\nname: test-chart\nversion: 0.1.0\n",
+		"templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\n",
+	})
+	failingChartPath := writeTestChart(t, map[string]string{
+		"Chart.yaml":             "apiVersion: v2\nname: failing-chart\nversion: 0.1.0\n",
+		"templates/failing.yaml": `{{ fail "broken chart" }}`,
+	})
+
+	spec := func(name, chart string, dependsOn ...string) *ChartSpec {
+		return &ChartSpec{ReleaseName: name, ChartName: chart, Namespace: "default", DependsOn: dependsOn}
+	}
+
+	t.Run("dependency order", func(t *testing.T) {
+		c := newTestClient(t)
+
+		specs := []*ChartSpec{
+			spec("app", chartPath, "database", "cache"),
+			spec("database", chartPath, "operator"),
+			spec("cache", chartPath),
+			spec("operator", chartPath),
+			spec("monitoring", chartPath, "default/operator"),
+		}
+
+		var mu sync.Mutex
+		var violations []string
+		opts := &ApplyReleasesOptions{
+			Concurrency: 2,
+			HelmOptions: &GenericHelmOptions{
+				ProgressObserver: ProgressObserverFunc(func(event ProgressEvent) {
+					if event.Type != ProgressChartFetched {
+						return
+					}
+
+					for _, s := range specs {
+						if s.ReleaseName != event.ReleaseName {
+							continue
+						}
+
+						for _, dependency := range s.DependsOn {
+							if _, err := c.ActionConfig.Releases.Deployed(strings.TrimPrefix(dependency, "default/")); err != nil {
+								mu.Lock()
+								violations = append(violations, event.ReleaseName+" started before "+dependency)
+								mu.Unlock()
+							}
+						}
+					}
+				}),
+			},
+		}
+
+		results, err := c.ApplyReleases(context.Background(), specs, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(violations) > 0 {
+			t.Errorf("expected releases to be applied after their dependencies: %v", violations)
+		}
+
+		for i, result := range results {
+			if result.ReleaseName != specs[i].ReleaseName {
+				t.Errorf("expected result %d for %q, got %q", i, specs[i].ReleaseName, result.ReleaseName)
+			}
+
+			if result.Err != nil || result.Skipped || result.Release == nil {
+				t.Errorf("expected %q to be deployed, got %+v", result.ReleaseName, result)
+			}
+		}
+	})
+
+	t.Run("concurrent upgrades", func(t *testing.T) {
+		c := newTestClient(t)
+
+		specs := []*ChartSpec{spec("app", chartPath), spec("database", chartPath), spec("cache", chartPath)}
+
+		for revision := 1; revision <= 2; revision++ {
+			results, err := c.ApplyReleases(context.Background(), specs, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, result := range results {
+				if result.Release == nil || result.Release.Version != revision {
+					t.Errorf("expected revision %d of %q, got %+v", revision, result.ReleaseName, result.Release)
+				}
+			}
+		}
+	})
+
+	t.Run("continue on failure", func(t *testing.T) {
+		c := newTestClient(t)
+
+		specs := []*ChartSpec{
+			spec("database", failingChartPath),
+			spec("app", chartPath, "database"),
+			spec("frontend", chartPath, "app"),
+			spec("cache", chartPath),
+		}
+
+		results, err := c.ApplyReleases(context.Background(), specs, &ApplyReleasesOptions{FailurePolicy: ApplyContinueOnFailure})
+		if err == nil {
+			t.Fatal("expected the failing release to be reported")
+		}
+
+		if results[0].Err == nil || results[0].Skipped {
+			t.Errorf("expected 'database' to fail, got %+v", results[0])
+		}
+
+		for _, result := range results[1:3] {
+			if !result.Skipped || !errors.Is(result.Err, ErrReleaseDependencyFailed) {
+				t.Errorf("expected %q to be skipped because of its failed dependency, got %+v", result.ReleaseName, result)
+			}
+		}
+
+		if results[3].Err != nil {
+			t.Errorf("expected 'cache' to be deployed, got %v", results[3].Err)
+		}
+	})
+
+	t.Run("stop on failure", func(t *testing.T) {
+		c := newTestClient(t)
+
+		specs := []*ChartSpec{
+			spec("database", failingChartPath),
+			spec("cache", chartPath),
+		}
+
+		results, err := c.ApplyReleases(context.Background(), specs, &ApplyReleasesOptions{Concurrency: 1})
+		if err == nil {
+			t.Fatal("expected the failing release to be reported")
+		}
+
+		if !results[1].Skipped || !errors.Is(results[1].Err, ErrApplyStopped) {
+			t.Errorf("expected 'cache' to be skipped, got %+v", results[1])
+		}
+	})
+
+	t.Run("namespaces and generated names", func(t *testing.T) {
+		c := newTestClient(t)
+
+		// Unlike the default generated names, the name template does not depend on the current second.
+		nameTemplate := "worker-{{ randAlphaNum 8 | lower }}"
+		specs := []*ChartSpec{
+			{ReleaseName: "app", ChartName: chartPath, DependsOn: []string{"default/database"}},
+			spec("database", chartPath),
+			{GenerateName: true, NameTemplate: nameTemplate, ChartName: chartPath, Namespace: "default", DependsOn: []string{"app"}},
+			{GenerateName: true, NameTemplate: nameTemplate, ChartName: chartPath, Namespace: "default"},
+		}
+
+		results, err := c.ApplyReleases(context.Background(), specs, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if results[0].Namespace != "default" {
+			t.Errorf("expected the release without a namespace to be applied to the client's namespace, got %q", results[0].Namespace)
+		}
+
+		if specs[0].Namespace != "" {
+			t.Errorf("expected the provided spec to be left unchanged, got the namespace %q", specs[0].Namespace)
+		}
+
+		if results[2].Release == nil || results[3].Release == nil || results[2].Release.Name == results[3].Release.Name {
+			t.Errorf("expected two releases with generated names, got %+v and %+v", results[2], results[3])
+		}
+	})
+
+	t.Run("spec validation", func(t *testing.T) {
+		c := newTestClient(t)
+
+		waiting := spec("jobs", chartPath)
+		waiting.WaitForJobs = true
+
+		validated := &ApplyReleasesOptions{HelmOptions: &GenericHelmOptions{ValidateSpec: true}}
+		if _, err := c.ApplyReleases(context.Background(), []*ChartSpec{waiting}, validated); !errors.Is(err, ErrInvalidChartSpec) {
+			t.Errorf("expected the invalid spec to be rejected, got %v", err)
+		}
+
+		results, err := c.ApplyReleases(context.Background(), []*ChartSpec{waiting}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if results[0].Release == nil {
+			t.Errorf("expected the spec to be applied without validation, got %+v", results[0])
+		}
+	})
+
+	t.Run("invalid dependencies", func(t *testing.T) {
+		c := newTestClient(t)
+
+		tests := map[string]struct {
+			specs    []*ChartSpec
+			expected error
+		}{
+			"cycle": {
+				specs:    []*ChartSpec{spec("a", chartPath, "b"), spec("b", chartPath, "c"), spec("c", chartPath, "a")},
+				expected: ErrReleaseDependencyCycle,
+			},
+			"missing": {
+				specs:    []*ChartSpec{spec("a", chartPath, "other/b"), spec("b", chartPath)},
+				expected: ErrReleaseDependencyNotFound,
+			},
+			"duplicate": {
+				specs:    []*ChartSpec{spec("a", chartPath), spec("a", chartPath)},
+				expected: ErrDuplicateRelease,
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := c.ApplyReleases(context.Background(), test.specs, nil)
+				if !errors.Is(err, test.expected) {
+					t.Errorf("expected %v, got %v", test.expected, err)
+				}
+			})
+		}
+
+		releases, err := c.ActionConfig.Releases.ListReleases()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(releases) != 0 {
+			t.Errorf("expected no release to be applied, got %d", len(releases))
+		}
+	})
+}
@@ -6,10 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
 	"slices"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 
@@ -20,9 +23,12 @@ import (
 	"helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/downloader"
 	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/kube"
 	"helm.sh/helm/v3/pkg/registry"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/repo"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
@@ -31,8 +37,6 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
-var storage = repo.File{}
-
 const (
 	defaultCachePath            = "/tmp/.helmcache"
 	defaultRepositoryConfigPath = "/tmp/.helmrepo"
@@ -54,7 +58,7 @@ func New(options *Options) (Client, error) {
 func NewClientFromKubeConf(options *KubeConfClientOptions, restClientOpts ...RESTClientOption) (Client, error) {
 	settings := cli.New()
 	if options.KubeConfig == nil {
-		return nil, fmt.Errorf("kubeconfig missing")
+		return nil, ErrKubeConfigMissing
 	}
 
 	clientGetter := NewRESTClientGetter(options.Namespace, options.KubeConfig, nil, restClientOpts...)
@@ -94,35 +98,99 @@ func newClient(options *Options, clientGetter genericclioptions.RESTClientGetter
 		options.Output = os.Stdout
 	}
 
+	releaseStorage := newReleaseStorageOptions(options)
+
 	actionConfig := new(action.Configuration)
-	err = actionConfig.Init(
-		clientGetter,
+	err = releaseStorage.initActionConfig(
+		actionConfig,
+		newSharedRESTClientGetter(clientGetter),
 		settings.Namespace(),
-		os.Getenv("HELM_DRIVER"),
 		debugLog,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	registryClient, err := registry.NewClient(
-		registry.ClientOptDebug(settings.Debug),
-		registry.ClientOptCredentialsFile(settings.RegistryConfig),
-	)
+	storage, err := loadRepositoryStorage(settings.RepositoryConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	helmClient := &HelmClient{
+		Settings:            settings,
+		Providers:           getter.All(settings),
+		storage:             storage,
+		registryCredentials: newRegistryCredentials(settings.RegistryConfig),
+		ActionConfig:        actionConfig,
+		releaseStorage:      releaseStorage,
+		operationObserver:   options.OperationObserver,
+		linting:             options.Linting,
+		DebugLog:            debugLog,
+		output:              options.Output,
+	}
+
+	registryClient, err := helmClient.newRegistryClient(nil)
 	if err != nil {
 		return nil, err
 	}
 	actionConfig.RegistryClient = registryClient
 
-	return &HelmClient{
-		Settings:     settings,
-		Providers:    getter.All(settings),
-		storage:      &storage,
-		ActionConfig: actionConfig,
-		linting:      options.Linting,
-		DebugLog:     debugLog,
-		output:       options.Output,
-	}, nil
+	return helmClient, nil
+}
+
+// newReleaseStorageOptions returns the release storage driver configured in the provided options,
+// falling back to the environment variables used by the helm CLI.
+func newReleaseStorageOptions(options *Options) releaseStorageOptions {
+	storageOptions := releaseStorageOptions{
+		driverName:          options.StorageDriver,
+		sqlConnectionString: options.SQLConnectionString,
+		driver:              options.Driver,
+	}
+
+	if storageOptions.driverName == "" {
+		storageOptions.driverName = os.Getenv("HELM_DRIVER")
+	}
+
+	if storageOptions.sqlConnectionString == "" {
+		storageOptions.sqlConnectionString = os.Getenv("HELM_DRIVER_SQL_CONNECTION_STRING")
+	}
+
+	if storageOptions.driver == nil && storageOptions.driverName == StorageDriverMemory {
+		storageOptions.memory = newMemoryReleases()
+	}
+
+	return storageOptions
+}
+
+// initActionConfig initializes the provided action configuration, storing releases of the provided namespace
+// with the configured driver.
+func (s releaseStorageOptions) initActionConfig(actionConfig *action.Configuration, getter genericclioptions.RESTClientGetter, namespace string, debugLog action.DebugLog) error {
+	driverName := s.driverName
+	if s.driver != nil || driverName == StorageDriverSQL {
+		// The storage is replaced below, so avoid connecting to a database twice.
+		driverName = StorageDriverMemory
+	}
+
+	err := actionConfig.Init(getter, namespace, driverName, debugLog)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case s.driver != nil:
+		actionConfig.Releases = storage.Init(s.driver)
+	case s.memory != nil:
+		actionConfig.Releases = storage.Init(s.memory.driver(namespace))
+	case s.driverName == StorageDriverSQL:
+		sqlDriver, err := driver.NewSQL(s.sqlConnectionString, debugLog, namespace)
+		if err != nil {
+			return fmt.Errorf("unable to instantiate SQL driver: %w", err)
+		}
+
+		actionConfig.Releases = storage.Init(sqlDriver)
+	}
+
+	return nil
 }
 
 // setEnvSettings sets the client's environment settings based on the provided client configuration.
@@ -137,12 +205,8 @@ func setEnvSettings(ppOptions **Options, settings *cli.EnvSettings) error {
 
 	options := *ppOptions
 
-	// set the namespace with this ugly workaround because cli.EnvSettings.namespace is private
-	// thank you helm!
 	if options.Namespace != "" {
-		pflags := pflag.NewFlagSet("", pflag.ContinueOnError)
-		settings.AddFlags(pflags)
-		err := pflags.Parse([]string{"-n", options.Namespace})
+		err := setNamespace(settings, options.Namespace)
 		if err != nil {
 			return err
 		}
@@ -167,62 +231,110 @@ func setEnvSettings(ppOptions **Options, settings *cli.EnvSettings) error {
 	return nil
 }
 
-// AddOrUpdateChartRepo adds or updates the provided helm chart repository.
-func (c *HelmClient) AddOrUpdateChartRepo(entry repo.Entry) error {
-	chartRepo, err := repo.NewChartRepository(&entry, c.Providers)
-	if err != nil {
-		return err
-	}
+// setNamespace sets the namespace of the provided settings.
+func setNamespace(settings *cli.EnvSettings, namespace string) error {
+	// set the namespace with this ugly workaround because cli.EnvSettings.namespace is private
+	// thank you helm!
+	pflags := pflag.NewFlagSet("", pflag.ContinueOnError)
+	settings.AddFlags(pflags)
 
-	chartRepo.CachePath = c.Settings.RepositoryCache
+	return pflags.Parse([]string{"-n", namespace})
+}
 
-	if c.storage.Has(entry.Name) {
-		c.DebugLog("WARNING: repository name %q already exists", entry.Name)
-		return nil
+// WithNamespace returns a client operating on the provided namespace. It shares the repository config,
+// registry credentials and client, getter providers and discovery cache with this client,
+// but stores releases in the provided namespace using its own action configuration.
+// Clients using the in-memory storage driver also share the stored releases.
+func (c *HelmClient) WithNamespace(namespace string) (Client, error) {
+	settings, err := namespacedSettings(c.Settings, namespace)
+	if err != nil {
+		return nil, err
 	}
 
-	if !registry.IsOCI(entry.URL) {
-		_, err = chartRepo.DownloadIndexFile()
-		if err != nil {
-			return err
-		}
+	clientGetter, ok := c.ActionConfig.RESTClientGetter.(genericclioptions.RESTClientGetter)
+	if !ok {
+		return nil, fmt.Errorf("REST client getter %T does not provide a kubeconfig", c.ActionConfig.RESTClientGetter)
 	}
 
-	c.storage.Update(&entry)
-	err = c.storage.WriteFile(c.Settings.RepositoryConfig, 0o644)
+	getter := newSharedRESTClientGetter(clientGetter).withNamespace(namespace)
+
+	actionConfig := new(action.Configuration)
+	err = c.releaseStorage.initActionConfig(actionConfig, getter, namespace, c.DebugLog)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	actionConfig.RegistryClient = c.ActionConfig.RegistryClient
 
-	return nil
-}
+	// Kube clients that are not connected via the REST client getter, e.g. in-memory ones for tests,
+	// provide the client for the namespace themselves.
+	if kubeClient, ok := c.ActionConfig.KubeClient.(namespacedKubeClient); ok {
+		actionConfig.KubeClient = kubeClient.ForNamespace(namespace)
+		actionConfig.Capabilities = c.ActionConfig.Capabilities
+	}
 
-// UpdateChartRepos updates the list of chart repositories stored in the client's cache.
-func (c *HelmClient) UpdateChartRepos() error {
-	for _, entry := range c.storage.Repositories {
-		chartRepo, err := repo.NewChartRepository(entry, c.Providers)
-		if err != nil {
-			return err
-		}
+	return &HelmClient{
+		Settings:            settings,
+		Providers:           c.Providers,
+		storage:             c.storage,
+		registryCredentials: c.registryCredentials,
+		ActionConfig:        actionConfig,
+		releaseStorage:      c.releaseStorage,
+		operationObserver:   c.operationObserver,
+		linting:             c.linting,
+		output:              c.output,
+		DebugLog:            c.DebugLog,
+	}, nil
+}
 
-		chartRepo.CachePath = c.Settings.RepositoryCache
-		if !registry.IsOCI(entry.URL) {
-			_, err = chartRepo.DownloadIndexFile()
-			if err != nil {
-				return err
-			}
-		}
+// namespacedKubeClient is a kube client providing a client for another namespace, see HelmClient.WithNamespace.
+type namespacedKubeClient interface {
+	ForNamespace(namespace string) kube.Interface
+}
 
-		c.storage.Update(entry)
+// namespacedSettings returns a copy of the provided settings using the provided namespace.
+// The copy is created via cli.New, since the settings hold kubeconfig flags bound to their own fields.
+func namespacedSettings(settings *cli.EnvSettings, namespace string) (*cli.EnvSettings, error) {
+	derived := cli.New()
+	derived.KubeConfig = settings.KubeConfig
+	derived.KubeContext = settings.KubeContext
+	derived.KubeToken = settings.KubeToken
+	derived.KubeAsUser = settings.KubeAsUser
+	derived.KubeAsGroups = settings.KubeAsGroups
+	derived.KubeAPIServer = settings.KubeAPIServer
+	derived.KubeCaFile = settings.KubeCaFile
+	derived.KubeInsecureSkipTLSVerify = settings.KubeInsecureSkipTLSVerify
+	derived.KubeTLSServerName = settings.KubeTLSServerName
+	derived.Debug = settings.Debug
+	derived.RegistryConfig = settings.RegistryConfig
+	derived.RepositoryConfig = settings.RepositoryConfig
+	derived.RepositoryCache = settings.RepositoryCache
+	derived.PluginsDirectory = settings.PluginsDirectory
+	derived.MaxHistory = settings.MaxHistory
+	derived.BurstLimit = settings.BurstLimit
+	derived.QPS = settings.QPS
+
+	if err := setNamespace(derived, namespace); err != nil {
+		return nil, err
 	}
 
-	return c.storage.WriteFile(c.Settings.RepositoryConfig, 0o644)
+	return derived, nil
 }
 
 // InstallOrUpgradeChart installs or upgrades the provided chart and returns the corresponding release.
 // Namespace and other context is provided via the helmclient.Options struct when instantiating a client.
+// If enabled via GenericHelmOptions.RecoverPendingRelease, a release stuck in a pending state is recovered first.
 func (c *HelmClient) InstallOrUpgradeChart(ctx context.Context, spec *ChartSpec, opts *GenericHelmOptions) (*release.Release, error) {
-	exists, err := c.chartExists(spec)
+	if opts != nil && opts.ValidateSpec {
+		if err := validateSpec(spec); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.recoverPendingRelease(ctx, spec, opts); err != nil {
+		return nil, err
+	}
+
+	exists, err := c.chartExists(ctx, spec)
 	if err != nil {
 		return nil, err
 	}
@@ -237,56 +349,122 @@ func (c *HelmClient) InstallOrUpgradeChart(ctx context.Context, spec *ChartSpec,
 // InstallChart installs the provided chart and returns the corresponding release.
 // Namespace and other context is provided via the helmclient.Options struct when instantiating a client.
 func (c *HelmClient) InstallChart(ctx context.Context, spec *ChartSpec, opts *GenericHelmOptions) (*release.Release, error) {
+	if opts != nil && opts.ValidateSpec {
+		if err := validateSpec(spec); err != nil {
+			return nil, err
+		}
+	}
+
 	return c.install(ctx, spec, opts)
 }
 
 // UpgradeChart upgrades the provided chart and returns the corresponding release.
 // Namespace and other context is provided via the helmclient.Options struct when instantiating a client.
 func (c *HelmClient) UpgradeChart(ctx context.Context, spec *ChartSpec, opts *GenericHelmOptions) (*release.Release, error) {
+	if opts != nil && opts.ValidateSpec {
+		if err := validateSpec(spec); err != nil {
+			return nil, err
+		}
+	}
+
 	return c.upgrade(ctx, spec, opts)
 }
 
 // ListDeployedReleases lists all deployed releases.
 // Namespace and other context is provided via the helmclient.Options struct when instantiating a client.
 func (c *HelmClient) ListDeployedReleases() ([]*release.Release, error) {
-	return c.listReleases(action.ListDeployed)
+	return c.ListDeployedReleasesWithContext(context.Background())
+}
+
+// ListDeployedReleasesWithContext lists all deployed releases.
+// Namespace and other context is provided via the helmclient.Options struct when instantiating a client.
+func (c *HelmClient) ListDeployedReleasesWithContext(ctx context.Context) ([]*release.Release, error) {
+	return c.listReleases(ctx, action.ListDeployed)
 }
 
 // ListReleasesByStateMask lists all releases filtered by stateMask.
 // Namespace and other context is provided via the helmclient.Options struct when instantiating a client.
 func (c *HelmClient) ListReleasesByStateMask(states action.ListStates) ([]*release.Release, error) {
-	return c.listReleases(states)
+	return c.ListReleasesByStateMaskWithContext(context.Background(), states)
+}
+
+// ListReleasesByStateMaskWithContext lists all releases filtered by stateMask.
+// Namespace and other context is provided via the helmclient.Options struct when instantiating a client.
+func (c *HelmClient) ListReleasesByStateMaskWithContext(ctx context.Context, states action.ListStates) ([]*release.Release, error) {
+	return c.listReleases(ctx, states)
 }
 
 // GetReleaseValues returns the (optionally, all computed) values for the specified release.
 func (c *HelmClient) GetReleaseValues(name string, allValues bool) (map[string]interface{}, error) {
-	return c.getReleaseValues(name, allValues)
+	return c.GetReleaseValuesWithContext(context.Background(), name, allValues)
+}
+
+// GetReleaseValuesWithContext returns the (optionally, all computed) values for the specified release.
+func (c *HelmClient) GetReleaseValuesWithContext(ctx context.Context, name string, allValues bool) (map[string]interface{}, error) {
+	return c.getReleaseValues(ctx, name, allValues)
 }
 
 // GetRelease returns a release specified by name.
 func (c *HelmClient) GetRelease(name string) (*release.Release, error) {
-	return c.getRelease(name)
+	return c.GetReleaseWithContext(context.Background(), name)
+}
+
+// GetReleaseWithContext returns a release specified by name.
+func (c *HelmClient) GetReleaseWithContext(ctx context.Context, name string) (*release.Release, error) {
+	return c.getRelease(ctx, name)
 }
 
 // RollbackRelease implicitly rolls back a release to the last revision.
 func (c *HelmClient) RollbackRelease(spec *ChartSpec) error {
-	return c.rollbackRelease(spec)
+	return c.RollbackReleaseWithContext(context.Background(), spec)
+}
+
+// RollbackReleaseWithContext implicitly rolls back a release to the last revision.
+// The deadline of the provided context bounds the configured timeout of the rollback.
+// If the context is done before the rollback completed, its error is returned immediately,
+// but the rollback is abandoned rather than aborted and keeps running in the background.
+func (c *HelmClient) RollbackReleaseWithContext(ctx context.Context, spec *ChartSpec) error {
+	return c.rollbackRelease(ctx, spec)
 }
 
 // UninstallRelease uninstalls the provided release
 func (c *HelmClient) UninstallRelease(spec *ChartSpec) error {
-	return c.uninstallRelease(spec)
+	return c.UninstallReleaseWithContext(context.Background(), spec)
+}
+
+// UninstallReleaseWithContext uninstalls the provided release.
+// The deadline of the provided context bounds the configured timeout of the uninstallation.
+// If the context is done before the uninstallation completed, its error is returned immediately,
+// but the uninstallation is abandoned rather than aborted and keeps running in the background.
+func (c *HelmClient) UninstallReleaseWithContext(ctx context.Context, spec *ChartSpec) error {
+	return c.uninstallRelease(ctx, spec)
 }
 
 // UninstallReleaseByName uninstalls a release identified by the provided 'name'.
 func (c *HelmClient) UninstallReleaseByName(name string) error {
-	return c.uninstallReleaseByName(name)
+	return c.UninstallReleaseByNameWithContext(context.Background(), name)
+}
+
+// UninstallReleaseByNameWithContext uninstalls a release identified by the provided 'name'.
+// If the context is done before the uninstallation completed, its error is returned immediately,
+// but the uninstallation is abandoned rather than aborted and keeps running in the background.
+func (c *HelmClient) UninstallReleaseByNameWithContext(ctx context.Context, name string) error {
+	return c.uninstallReleaseByName(ctx, name)
 }
 
 // install installs the provided chart.
 // Optionally lints the chart if the linting flag is set.
-func (c *HelmClient) install(ctx context.Context, spec *ChartSpec, opts *GenericHelmOptions) (*release.Release, error) {
-	client := action.NewInstall(c.ActionConfig)
+func (c *HelmClient) install(ctx context.Context, spec *ChartSpec, opts *GenericHelmOptions) (_ *release.Release, err error) {
+	defer c.observeOperation(OperationEvent{Operation: OperationInstall, ReleaseName: spec.ReleaseName, Namespace: spec.Namespace, Chart: spec.ChartName}, time.Now(), &err)
+
+	actionConfig, err := c.actionConfig(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := newProgressReporter(opts, spec.ReleaseName)
+
+	client := action.NewInstall(progress.actionConfig(actionConfig))
 	mergeInstallOptions(spec, client)
 
 	// NameAndChart returns either the TemplateName if set,
@@ -296,6 +474,9 @@ func (c *HelmClient) install(ctx context.Context, spec *ChartSpec, opts *Generic
 		return nil, err
 	}
 	client.ReleaseName = releaseName
+	if progress != nil {
+		progress.releaseName = releaseName
+	}
 
 	if client.Version == "" {
 		client.Version = ">0.0.0-0"
@@ -307,35 +488,23 @@ func (c *HelmClient) install(ctx context.Context, spec *ChartSpec, opts *Generic
 		}
 	}
 
-	helmChart, chartPath, err := c.GetChart(spec.ChartName, &client.ChartPathOptions)
-	if err != nil {
-		return nil, err
-	}
-
-	if helmChart.Metadata.Type != "" && helmChart.Metadata.Type != "application" {
-		return nil, fmt.Errorf(
-			"chart %q has an unsupported type and is not installable: %q",
-			helmChart.Metadata.Name,
-			helmChart.Metadata.Type,
-		)
-	}
-
-	helmChart, err = updateDependencies(helmChart, &client.ChartPathOptions, chartPath, c, client.DependencyUpdate, spec)
-	if err != nil {
-		return nil, err
-	}
-
-	p := getter.All(c.Settings)
-	values, err := spec.GetValuesMap(p)
+	helmChart, chartPath, values, err := c.loadChartAndValues(ctx, spec, &client.ChartPathOptions, client.DependencyUpdate, progress)
 	if err != nil {
 		return nil, err
 	}
 
 	if c.linting {
-		err = c.lint(chartPath, values)
+		var lintOptions *LintOptions
+		if opts != nil {
+			lintOptions = opts.LintOptions
+		}
+
+		_, err = c.lint(spec, chartPath, values, lintOptions)
 		if err != nil {
 			return nil, err
 		}
+
+		progress.report(ProgressEvent{Type: ProgressLinted, Message: "chart linted successfully"})
 	}
 
 	rel, err := client.RunWithContext(ctx, helmChart, values)
@@ -350,8 +519,17 @@ func (c *HelmClient) install(ctx context.Context, spec *ChartSpec, opts *Generic
 
 // upgrade upgrades a chart and CRDs.
 // Optionally lints the chart if the linting flag is set.
-func (c *HelmClient) upgrade(ctx context.Context, spec *ChartSpec, opts *GenericHelmOptions) (*release.Release, error) {
-	client := action.NewUpgrade(c.ActionConfig)
+func (c *HelmClient) upgrade(ctx context.Context, spec *ChartSpec, opts *GenericHelmOptions) (_ *release.Release, err error) {
+	defer c.observeOperation(OperationEvent{Operation: OperationUpgrade, ReleaseName: spec.ReleaseName, Namespace: spec.Namespace, Chart: spec.ChartName}, time.Now(), &err)
+
+	actionConfig, err := c.actionConfig(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := newProgressReporter(opts, spec.ReleaseName)
+
+	client := action.NewUpgrade(progress.actionConfig(actionConfig))
 	mergeUpgradeOptions(spec, client)
 	client.Install = true
 
@@ -365,27 +543,23 @@ func (c *HelmClient) upgrade(ctx context.Context, spec *ChartSpec, opts *Generic
 		}
 	}
 
-	helmChart, chartPath, err := c.GetChart(spec.ChartName, &client.ChartPathOptions)
-	if err != nil {
-		return nil, err
-	}
-
-	helmChart, err = updateDependencies(helmChart, &client.ChartPathOptions, chartPath, c, client.DependencyUpdate, spec)
-	if err != nil {
-		return nil, err
-	}
-
-	p := getter.All(c.Settings)
-	values, err := spec.GetValuesMap(p)
+	helmChart, chartPath, values, err := c.loadChartAndValues(ctx, spec, &client.ChartPathOptions, client.DependencyUpdate, progress)
 	if err != nil {
 		return nil, err
 	}
 
 	if c.linting {
-		err = c.lint(chartPath, values)
+		var lintOptions *LintOptions
+		if opts != nil {
+			lintOptions = opts.LintOptions
+		}
+
+		_, err = c.lint(spec, chartPath, values, lintOptions)
 		if err != nil {
 			return nil, err
 		}
+
+		progress.report(ProgressEvent{Type: ProgressLinted, Message: "chart linted successfully"})
 	}
 
 	if !spec.SkipCRDs && spec.UpgradeCRDs {
@@ -394,19 +568,18 @@ func (c *HelmClient) upgrade(ctx context.Context, spec *ChartSpec, opts *Generic
 		if err != nil {
 			return nil, err
 		}
+
+		progress.report(ProgressEvent{Type: ProgressCRDsUpgraded, Message: fmt.Sprintf("upgraded %d CRDs", len(helmChart.CRDObjects()))})
 	}
 
 	upgradedRelease, upgradeErr := client.RunWithContext(ctx, spec.ReleaseName, helmChart, values)
 	if upgradeErr != nil {
-		resultErr := upgradeErr
-		if upgradedRelease == nil && opts != nil && opts.RollBack != nil {
-			rollbackErr := opts.RollBack.RollbackRelease(spec)
-			if rollbackErr != nil {
-				resultErr = fmt.Errorf("release failed, rollback failed: release error: %w, rollback error: %v", upgradeErr, rollbackErr)
-			} else {
-				resultErr = fmt.Errorf("release failed, rollback succeeded: release error: %w", upgradeErr)
-			}
+		resultErr := &UpgradeError{
+			ReleaseName: spec.ReleaseName,
+			Err:         releaseError(spec.ReleaseName, upgradeErr),
+			Rollback:    RollbackNotAttempted,
 		}
+		resultErr.Rollback, resultErr.RollbackErr = rollbackFailedUpgrade(ctx, spec, opts, upgradedRelease, resultErr.Err)
 		c.DebugLog("release upgrade failed: %s", resultErr)
 		return nil, resultErr
 	}
@@ -417,14 +590,19 @@ func (c *HelmClient) upgrade(ctx context.Context, spec *ChartSpec, opts *Generic
 }
 
 // uninstallRelease uninstalls the provided release.
-func (c *HelmClient) uninstallRelease(spec *ChartSpec) error {
+func (c *HelmClient) uninstallRelease(ctx context.Context, spec *ChartSpec) (err error) {
+	defer c.observeOperation(OperationEvent{Operation: OperationUninstall, ReleaseName: spec.ReleaseName, Namespace: spec.Namespace}, time.Now(), &err)
+
 	client := action.NewUninstall(c.ActionConfig)
 
 	mergeUninstallReleaseOptions(spec, client)
+	client.Timeout = timeoutWithContext(ctx, client.Timeout)
 
-	resp, err := client.Run(spec.ReleaseName)
+	resp, err := runWithContext(ctx, func() (*release.UninstallReleaseResponse, error) {
+		return client.Run(spec.ReleaseName)
+	})
 	if err != nil {
-		return err
+		return releaseError(spec.ReleaseName, err)
 	}
 
 	c.DebugLog("release uninstalled, response: %v", resp)
@@ -433,12 +611,17 @@ func (c *HelmClient) uninstallRelease(spec *ChartSpec) error {
 }
 
 // uninstallReleaseByName uninstalls a release identified by the provided 'name'.
-func (c *HelmClient) uninstallReleaseByName(name string) error {
+func (c *HelmClient) uninstallReleaseByName(ctx context.Context, name string) (err error) {
+	defer c.observeOperation(OperationEvent{Operation: OperationUninstall, ReleaseName: name, Namespace: c.Settings.Namespace()}, time.Now(), &err)
+
 	client := action.NewUninstall(c.ActionConfig)
+	client.Timeout = timeoutWithContext(ctx, client.Timeout)
 
-	resp, err := client.Run(name)
+	resp, err := runWithContext(ctx, func() (*release.UninstallReleaseResponse, error) {
+		return client.Run(name)
+	})
 	if err != nil {
-		return err
+		return releaseError(name, err)
 	}
 
 	c.DebugLog("release uninstalled, response: %v", resp)
@@ -446,26 +629,21 @@ func (c *HelmClient) uninstallReleaseByName(name string) error {
 	return nil
 }
 
-// lint lints a chart's values.
-func (c *HelmClient) lint(chartPath string, values map[string]interface{}) error {
-	client := action.NewLint()
-
-	result := client.Run([]string{chartPath}, values)
+// TemplateChart returns a rendered version of the provided ChartSpec 'spec' by performing a "dry-run" install.
+func (c *HelmClient) TemplateChart(spec *ChartSpec, options *HelmTemplateOptions) ([]byte, error) {
+	return c.TemplateChartWithContext(context.Background(), spec, options)
+}
 
-	for _, err := range result.Errors {
-		c.DebugLog("Error %s", err)
-	}
+// TemplateChartWithContext returns a rendered version of the provided ChartSpec 'spec' by performing a "dry-run" install.
+func (c *HelmClient) TemplateChartWithContext(ctx context.Context, spec *ChartSpec, options *HelmTemplateOptions) (_ []byte, err error) {
+	defer c.observeOperation(OperationEvent{Operation: OperationTemplate, ReleaseName: spec.ReleaseName, Namespace: spec.Namespace, Chart: spec.ChartName}, time.Now(), &err)
 
-	if len(result.Errors) > 0 {
-		return fmt.Errorf("linting for chartpath %q failed", chartPath)
+	actionConfig, err := c.actionConfig(spec)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
-}
-
-// TemplateChart returns a rendered version of the provided ChartSpec 'spec' by performing a "dry-run" install.
-func (c *HelmClient) TemplateChart(spec *ChartSpec, options *HelmTemplateOptions) ([]byte, error) {
-	client := action.NewInstall(c.ActionConfig)
+	client := action.NewInstall(actionConfig)
 	mergeInstallOptions(spec, client)
 
 	client.DryRun = true
@@ -491,32 +669,13 @@ func (c *HelmClient) TemplateChart(spec *ChartSpec, options *HelmTemplateOptions
 		client.Version = ">0.0.0-0"
 	}
 
-	helmChart, chartPath, err := c.GetChart(spec.ChartName, &client.ChartPathOptions)
-	if err != nil {
-		return nil, err
-	}
-
-	if helmChart.Metadata.Type != "" && helmChart.Metadata.Type != "application" {
-		return nil, fmt.Errorf(
-			"chart %q has an unsupported type and is not installable: %q",
-			helmChart.Metadata.Name,
-			helmChart.Metadata.Type,
-		)
-	}
-
-	helmChart, err = updateDependencies(helmChart, &client.ChartPathOptions, chartPath, c, client.DependencyUpdate, spec)
-	if err != nil {
-		return nil, err
-	}
-
-	p := getter.All(c.Settings)
-	values, err := spec.GetValuesMap(p)
+	helmChart, _, values, err := c.loadChartAndValues(ctx, spec, &client.ChartPathOptions, client.DependencyUpdate, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	out := new(bytes.Buffer)
-	rel, err := client.Run(helmChart, values)
+	rel, err := client.RunWithContext(ctx, helmChart, values)
 
 	// We ignore a potential error here because, when the --debug flag was specified,
 	// we always want to print the YAML, even if it is not valid. The error is still returned afterwards.
@@ -537,24 +696,6 @@ func (c *HelmClient) TemplateChart(spec *ChartSpec, options *HelmTemplateOptions
 	return out.Bytes(), err
 }
 
-// LintChart fetches a chart using the provided ChartSpec 'spec' and lints it's values.
-func (c *HelmClient) LintChart(spec *ChartSpec) error {
-	_, chartPath, err := c.GetChart(spec.ChartName, &action.ChartPathOptions{
-		Version: spec.Version,
-	})
-	if err != nil {
-		return err
-	}
-
-	p := getter.All(c.Settings)
-	values, err := spec.GetValuesMap(p)
-	if err != nil {
-		return err
-	}
-
-	return c.lint(chartPath, values)
-}
-
 // SetDebugLog set's a Helm client's DebugLog to the desired 'debugLog'.
 func (c *HelmClient) SetDebugLog(debugLog action.DebugLog) {
 	c.DebugLog = debugLog
@@ -563,15 +704,27 @@ func (c *HelmClient) SetDebugLog(debugLog action.DebugLog) {
 // ListReleaseHistory lists the last 'max' number of entries
 // in the history of the release identified by 'name'.
 func (c *HelmClient) ListReleaseHistory(name string, max int) ([]*release.Release, error) {
+	return c.ListReleaseHistoryWithContext(context.Background(), name, max)
+}
+
+// ListReleaseHistoryWithContext lists the last 'max' number of entries
+// in the history of the release identified by 'name'.
+func (c *HelmClient) ListReleaseHistoryWithContext(ctx context.Context, name string, max int) ([]*release.Release, error) {
 	client := action.NewHistory(c.ActionConfig)
 
 	client.Max = max
 
-	return client.Run(name)
+	history, err := runWithContext(ctx, func() ([]*release.Release, error) {
+		return client.Run(name)
+	})
+
+	return history, releaseError(name, err)
 }
 
 // upgradeCRDs upgrades the CRDs of the provided chart.
-func (c *HelmClient) upgradeCRDs(ctx context.Context, chartInstance *chart.Chart) error {
+func (c *HelmClient) upgradeCRDs(ctx context.Context, chartInstance *chart.Chart) (err error) {
+	defer c.observeOperation(OperationEvent{Operation: OperationCRDUpgrade, Chart: chartInstance.Metadata.Name}, time.Now(), &err)
+
 	cfg, err := c.ActionConfig.RESTClientGetter.ToRESTConfig()
 	if err != nil {
 		return err
@@ -609,7 +762,10 @@ func (c *HelmClient) upgradeCRD(ctx context.Context, k8sClient *clientset.Client
 
 	switch typeMeta.APIVersion {
 	default:
-		return fmt.Errorf("WARNING: failed to upgrade CRD %q: unsupported api-version %q", crd.Name, typeMeta.APIVersion)
+		return &CRDUpgradeError{
+			CRD: crd.Name,
+			Err: fmt.Errorf("%w %q", ErrCRDUnsupportedAPIVersion, typeMeta.APIVersion),
+		}
 	case "apiextensions.k8s.io/v1beta1":
 		return c.upgradeCRDV1Beta1(ctx, k8sClient, jsonCRD)
 	case "apiextensions.k8s.io/v1":
@@ -666,11 +822,14 @@ func (c *HelmClient) upgradeCRDV1Beta1(ctx context.Context, cl *clientset.Client
 		if newVersion.Storage {
 			i++
 			if newVersion.Name != oldStorageVersion.Name {
-				return fmt.Errorf("ERROR: storage version of CRD %q changed, aborting upgrade", crdObj.Name)
+				return &CRDUpgradeError{
+					CRD: crdObj.Name,
+					Err: fmt.Errorf("%w from %q to %q", ErrCRDStorageVersionChanged, oldStorageVersion.Name, newVersion.Name),
+				}
 			}
 		}
 		if i > 1 {
-			return fmt.Errorf("ERROR: more than one storage version set on CRD %q, aborting upgrade", crdObj.Name)
+			return &CRDUpgradeError{CRD: crdObj.Name, Err: ErrCRDMultipleStorageVersions}
 		}
 	}
 
@@ -730,11 +889,14 @@ func (c *HelmClient) upgradeCRDV1(ctx context.Context, cl *clientset.Clientset,
 		if newVersion.Storage {
 			i++
 			if newVersion.Name != oldStorageVersion.Name {
-				return fmt.Errorf("ERROR: storage version of CRD %q changed, aborting upgrade", crdObj.Name)
+				return &CRDUpgradeError{
+					CRD: crdObj.Name,
+					Err: fmt.Errorf("%w from %q to %q", ErrCRDStorageVersionChanged, oldStorageVersion.Name, newVersion.Name),
+				}
 			}
 		}
 		if i > 1 {
-			return fmt.Errorf("ERROR: more than one storage version set on CRD %q, aborting upgrade", crdObj.Name)
+			return &CRDUpgradeError{CRD: crdObj.Name, Err: ErrCRDMultipleStorageVersions}
 		}
 	}
 
@@ -757,9 +919,43 @@ func (c *HelmClient) upgradeCRDV1(ctx context.Context, cl *clientset.Clientset,
 	return nil
 }
 
+// actionConfig returns the action configuration used for the provided ChartSpec 'spec'.
+// OCI charts with inline registry settings are located with a copy of the client's configuration
+// that uses a dedicated registry client.
+func (c *HelmClient) actionConfig(spec *ChartSpec) (*action.Configuration, error) {
+	if !registry.IsOCI(spec.ChartName) || !spec.hasRegistrySettings() {
+		return c.ActionConfig, nil
+	}
+
+	registryClient, err := c.newChartSpecRegistryClient(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	actionConfig := *c.ActionConfig
+	actionConfig.RegistryClient = registryClient
+
+	return &actionConfig, nil
+}
+
 // GetChart returns a chart matching the provided chart name and options.
 func (c *HelmClient) GetChart(chartName string, chartPathOptions *action.ChartPathOptions) (*chart.Chart, string, error) {
-	chartPath, err := chartPathOptions.LocateChart(chartName, c.Settings)
+	return c.GetChartWithContext(context.Background(), chartName, chartPathOptions)
+}
+
+// GetChartWithContext returns a chart matching the provided chart name and options.
+// Downloads from chart repositories are cancelled with the provided context. OCI charts are pulled
+// by the registry client of the options, so such a pull is abandoned rather than aborted and
+// may still store the chart in the repository cache after the context's error was returned.
+func (c *HelmClient) GetChartWithContext(ctx context.Context, chartName string, chartPathOptions *action.ChartPathOptions) (*chart.Chart, string, error) {
+	return c.getChart(ctx, chartName, chartPathOptions)
+}
+
+// getChart returns a chart matching the provided chart name and options.
+func (c *HelmClient) getChart(ctx context.Context, chartName string, chartPathOptions *action.ChartPathOptions) (_ *chart.Chart, _ string, err error) {
+	defer c.observeOperation(OperationEvent{Operation: OperationChartFetch, Chart: chartName}, time.Now(), &err)
+
+	chartPath, err := c.locateChart(ctx, chartName, chartPathOptions)
 	if err != nil {
 		return nil, "", err
 	}
@@ -776,33 +972,156 @@ func (c *HelmClient) GetChart(chartName string, chartPathOptions *action.ChartPa
 	return helmChart, chartPath, err
 }
 
+// locateChart returns the path of the chart with the provided name like action.ChartPathOptions.LocateChart,
+// but downloads charts from repositories via the client's getter providers bound to ctx.
+// Local and OCI charts are located by LocateChart itself, since OCI charts are pulled by the registry client
+// configured on the options, which can only be abandoned on cancellation. Repository charts whose URL refers to
+// an OCI registry are pulled by the client's registry client.
+func (c *HelmClient) locateChart(ctx context.Context, name string, chartPathOptions *action.ChartPathOptions) (string, error) {
+	name = strings.TrimSpace(name)
+
+	if _, err := os.Stat(name); err == nil || registry.IsOCI(name) || filepath.IsAbs(name) || strings.HasPrefix(name, ".") {
+		return runWithContext(ctx, func() (string, error) {
+			return chartPathOptions.LocateChart(name, c.Settings)
+		})
+	}
+
+	providers := contextProviders(ctx, c.Providers)
+	version := strings.TrimSpace(chartPathOptions.Version)
+
+	chartDownloader := downloader.ChartDownloader{
+		Out:     c.output,
+		Keyring: chartPathOptions.Keyring,
+		Getters: providers,
+		Options: []getter.Option{
+			getter.WithPassCredentialsAll(chartPathOptions.PassCredentialsAll),
+			getter.WithTLSClientConfig(chartPathOptions.CertFile, chartPathOptions.KeyFile, chartPathOptions.CaFile),
+			getter.WithInsecureSkipVerifyTLS(chartPathOptions.InsecureSkipTLSverify),
+			getter.WithPlainHTTP(chartPathOptions.PlainHTTP),
+		},
+		RegistryClient:   c.ActionConfig.RegistryClient,
+		RepositoryConfig: c.Settings.RepositoryConfig,
+		RepositoryCache:  c.Settings.RepositoryCache,
+	}
+
+	if chartPathOptions.Verify {
+		chartDownloader.Verify = downloader.VerifyAlways
+	}
+
+	username, password := chartPathOptions.Username, chartPathOptions.Password
+	if chartPathOptions.RepoURL != "" {
+		chartURL, err := runWithContext(ctx, func() (string, error) {
+			return repo.FindChartInAuthAndTLSAndPassRepoURL(chartPathOptions.RepoURL, username, password, name, version,
+				chartPathOptions.CertFile, chartPathOptions.KeyFile, chartPathOptions.CaFile,
+				chartPathOptions.InsecureSkipTLSverify, chartPathOptions.PassCredentialsAll, providers)
+		})
+		if err != nil {
+			return "", err
+		}
+
+		repoURL, err := url.Parse(chartPathOptions.RepoURL)
+		if err != nil {
+			return "", err
+		}
+
+		parsedChartURL, err := url.Parse(chartURL)
+		if err != nil {
+			return "", err
+		}
+
+		// Like helm, only pass the credentials on if the chart is served by the repository's host or if requested.
+		if !chartPathOptions.PassCredentialsAll && (repoURL.Scheme != parsedChartURL.Scheme || repoURL.Host != parsedChartURL.Host) {
+			username, password = "", ""
+		}
+
+		name = chartURL
+	}
+
+	chartDownloader.Options = append(chartDownloader.Options, getter.WithBasicAuth(username, password))
+
+	if err := os.MkdirAll(c.Settings.RepositoryCache, 0o755); err != nil {
+		return "", err
+	}
+
+	chartPath, err := runWithContext(ctx, func() (string, error) {
+		saved, _, err := chartDownloader.DownloadTo(name, version, c.Settings.RepositoryCache)
+		return saved, err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Abs(chartPath)
+}
+
 // RunTests runs the tests that were deployed with the release provided. It returns true
 // if all the tests ran successfully and false in all other cases.
 // NOTE: error = nil implies that all tests ran to either success or failure.
 func (c *HelmClient) RunChartTests(releaseName string) (bool, error) {
+	return c.RunChartTestsWithContext(context.Background(), releaseName)
+}
 
+// RunChartTestsWithContext runs the tests that were deployed with the release provided. It returns true
+// if all the tests ran successfully and false in all other cases.
+// The deadline of the provided context bounds the time spent waiting for the test pods.
+// NOTE: error = nil implies that all tests ran to either success or failure.
+func (c *HelmClient) RunChartTestsWithContext(ctx context.Context, releaseName string) (bool, error) {
 	client := action.NewReleaseTesting(c.ActionConfig)
 
 	if c.Settings.Namespace() == "" {
-		return false, fmt.Errorf("namespace not set")
+		return false, ErrNamespaceNotSet
 	}
 
 	client.Namespace = c.Settings.Namespace()
+	client.Timeout = timeoutWithContext(ctx, client.Timeout)
 
-	rel, err := client.Run(releaseName)
+	rel, err := runWithContext(ctx, func() (*release.Release, error) {
+		return client.Run(releaseName)
+	})
 	if err != nil && rel == nil {
-		return false, fmt.Errorf("unable to find release '%s': %v", releaseName, err)
+		return false, fmt.Errorf("unable to find release '%s': %w", releaseName, releaseError(releaseName, err))
 	}
 
 	// Check that there are no test failures
 	return !checkReleaseForTestFailure(rel), nil
 }
 
+// loadChartAndValues fetches the chart of the provided ChartSpec 'spec', updates its dependencies
+// and returns it along with its path and the merged values of the spec.
+func (c *HelmClient) loadChartAndValues(ctx context.Context, spec *ChartSpec, chartPathOptions *action.ChartPathOptions, dependencyUpdate bool, progress *progressReporter) (*chart.Chart, string, map[string]interface{}, error) {
+	helmChart, chartPath, err := c.getChart(ctx, spec.ChartName, chartPathOptions)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	progress.report(chartFetchedEvent(helmChart))
+
+	if helmChart.Metadata.Type != "" && helmChart.Metadata.Type != "application" {
+		return nil, "", nil, &UnsupportedChartTypeError{
+			Chart: helmChart.Metadata.Name,
+			Type:  helmChart.Metadata.Type,
+		}
+	}
+
+	helmChart, err = updateDependencies(ctx, helmChart, chartPathOptions, chartPath, c, dependencyUpdate, spec, progress)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	p := getter.All(c.Settings)
+	values, err := spec.GetValuesMap(p)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return helmChart, chartPath, values, nil
+}
+
 // chartExists checks whether a chart is already installed
 // in a namespace or not based on the provided chart spec.
 // Note that this function only considers the contained chart name and namespace.
-func (c *HelmClient) chartExists(spec *ChartSpec) (bool, error) {
-	releases, err := c.listReleases(action.ListAll)
+func (c *HelmClient) chartExists(ctx context.Context, spec *ChartSpec) (bool, error) {
+	releases, err := c.listReleases(ctx, action.ListAll)
 	if err != nil {
 		return false, err
 	}
@@ -817,41 +1136,56 @@ func (c *HelmClient) chartExists(spec *ChartSpec) (bool, error) {
 }
 
 // listReleases lists all releases that match the given state.
-func (c *HelmClient) listReleases(state action.ListStates) ([]*release.Release, error) {
+func (c *HelmClient) listReleases(ctx context.Context, state action.ListStates) ([]*release.Release, error) {
 	listClient := action.NewList(c.ActionConfig)
 	listClient.StateMask = state
 
-	return listClient.Run()
+	return runWithContext(ctx, listClient.Run)
 }
 
 // getReleaseValues returns the values for the provided release 'name'.
 // If allValues = true is specified, all computed values are returned.
-func (c *HelmClient) getReleaseValues(name string, allValues bool) (map[string]interface{}, error) {
+func (c *HelmClient) getReleaseValues(ctx context.Context, name string, allValues bool) (map[string]interface{}, error) {
 	getReleaseValuesClient := action.NewGetValues(c.ActionConfig)
 
 	getReleaseValuesClient.AllValues = allValues
 
-	return getReleaseValuesClient.Run(name)
+	values, err := runWithContext(ctx, func() (map[string]interface{}, error) {
+		return getReleaseValuesClient.Run(name)
+	})
+
+	return values, releaseError(name, err)
 }
 
 // getRelease returns a release matching the provided 'name'.
-func (c *HelmClient) getRelease(name string) (*release.Release, error) {
+func (c *HelmClient) getRelease(ctx context.Context, name string) (*release.Release, error) {
 	getReleaseClient := action.NewGet(c.ActionConfig)
 
-	return getReleaseClient.Run(name)
+	rel, err := runWithContext(ctx, func() (*release.Release, error) {
+		return getReleaseClient.Run(name)
+	})
+
+	return rel, releaseError(name, err)
 }
 
 // rollbackRelease implicitly rolls back a release to the last revision.
-func (c *HelmClient) rollbackRelease(spec *ChartSpec) error {
+func (c *HelmClient) rollbackRelease(ctx context.Context, spec *ChartSpec) (err error) {
+	defer c.observeOperation(OperationEvent{Operation: OperationRollback, ReleaseName: spec.ReleaseName, Namespace: spec.Namespace}, time.Now(), &err)
+
 	client := action.NewRollback(c.ActionConfig)
 
 	mergeRollbackOptions(spec, client)
+	client.Timeout = timeoutWithContext(ctx, client.Timeout)
+
+	_, err = runWithContext(ctx, func() (struct{}, error) {
+		return struct{}{}, client.Run(spec.ReleaseName)
+	})
 
-	return client.Run(spec.ReleaseName)
+	return releaseError(spec.ReleaseName, err)
 }
 
 // updateDependencies checks dependencies for given helmChart and updates dependencies with metadata if dependencyUpdate is true. returns updated HelmChart
-func updateDependencies(helmChart *chart.Chart, chartPathOptions *action.ChartPathOptions, chartPath string, c *HelmClient, dependencyUpdate bool, spec *ChartSpec) (*chart.Chart, error) {
+func updateDependencies(ctx context.Context, helmChart *chart.Chart, chartPathOptions *action.ChartPathOptions, chartPath string, c *HelmClient, dependencyUpdate bool, spec *ChartSpec, progress *progressReporter) (*chart.Chart, error) {
 	if req := helmChart.Metadata.Dependencies; req != nil {
 		if err := action.CheckDependencies(helmChart, req); err != nil {
 			if dependencyUpdate {
@@ -859,7 +1193,7 @@ func updateDependencies(helmChart *chart.Chart, chartPathOptions *action.ChartPa
 					ChartPath:        chartPath,
 					Keyring:          chartPathOptions.Keyring,
 					SkipUpdate:       false,
-					Getters:          c.Providers,
+					Getters:          contextProviders(ctx, c.Providers),
 					RepositoryConfig: c.Settings.RepositoryConfig,
 					RepositoryCache:  c.Settings.RepositoryCache,
 					Out:              c.output,
@@ -868,11 +1202,13 @@ func updateDependencies(helmChart *chart.Chart, chartPathOptions *action.ChartPa
 					return nil, err
 				}
 
-				helmChart, _, err = c.GetChart(spec.ChartName, chartPathOptions)
+				helmChart, _, err = c.getChart(ctx, spec.ChartName, chartPathOptions)
 				if err != nil {
 					return nil, err
 				}
 
+				progress.report(ProgressEvent{Type: ProgressDependenciesUpdated, Message: fmt.Sprintf("updated %d dependencies", len(req))})
+
 			} else {
 				return nil, err
 			}
@@ -900,15 +1236,23 @@ func checkReleaseForTestFailure(rel *release.Release) bool {
 
 // mergeRollbackOptions merges values of the provided chart to helm rollback options used by the client.
 func mergeRollbackOptions(chartSpec *ChartSpec, rollbackOptions *action.Rollback) {
-	rollbackOptions.DisableHooks = chartSpec.DisableHooks
-	rollbackOptions.DryRun = chartSpec.DryRun
-	rollbackOptions.Timeout = chartSpec.Timeout
-	rollbackOptions.CleanupOnFail = chartSpec.CleanupOnFail
-	rollbackOptions.Force = chartSpec.Force
-	rollbackOptions.MaxHistory = chartSpec.MaxHistory
-	rollbackOptions.Recreate = chartSpec.Recreate
-	rollbackOptions.Wait = chartSpec.Wait
-	rollbackOptions.WaitForJobs = chartSpec.WaitForJobs
+	mergeRollbackToRevisionOptions(rollbackOptionsFromSpec(chartSpec), rollbackOptions)
+}
+
+// mergeChartPathOptions merges the repository settings of the provided chart to helm chart path options used by the client.
+func mergeChartPathOptions(chartSpec *ChartSpec, chartPathOptions *action.ChartPathOptions) {
+	chartPathOptions.Version = chartSpec.Version
+	chartPathOptions.RepoURL = chartSpec.RepoURL
+	chartPathOptions.Username = chartSpec.Username
+	chartPathOptions.Password = chartSpec.Password
+	chartPathOptions.PassCredentialsAll = chartSpec.PassCredentialsAll
+	chartPathOptions.CaFile = chartSpec.CAFile
+	chartPathOptions.CertFile = chartSpec.CertFile
+	chartPathOptions.KeyFile = chartSpec.KeyFile
+	chartPathOptions.InsecureSkipTLSverify = chartSpec.InsecureSkipTLSVerify
+	chartPathOptions.PlainHTTP = chartSpec.PlainHTTP
+	chartPathOptions.Verify = chartSpec.Verify
+	chartPathOptions.Keyring = chartSpec.keyring()
 }
 
 // mergeInstallOptions merges values of the provided chart to helm install options used by the client.
@@ -921,7 +1265,7 @@ func mergeInstallOptions(chartSpec *ChartSpec, installOptions *action.Install) {
 	installOptions.Timeout = chartSpec.Timeout
 	installOptions.Namespace = chartSpec.Namespace
 	installOptions.ReleaseName = chartSpec.ReleaseName
-	installOptions.Version = chartSpec.Version
+	mergeChartPathOptions(chartSpec, &installOptions.ChartPathOptions)
 	installOptions.GenerateName = chartSpec.GenerateName
 	installOptions.NameTemplate = chartSpec.NameTemplate
 	installOptions.Atomic = chartSpec.Atomic
@@ -935,7 +1279,7 @@ func mergeInstallOptions(chartSpec *ChartSpec, installOptions *action.Install) {
 
 // mergeUpgradeOptions merges values of the provided chart to helm upgrade options used by the client.
 func mergeUpgradeOptions(chartSpec *ChartSpec, upgradeOptions *action.Upgrade) {
-	upgradeOptions.Version = chartSpec.Version
+	mergeChartPathOptions(chartSpec, &upgradeOptions.ChartPathOptions)
 	upgradeOptions.Namespace = chartSpec.Namespace
 	upgradeOptions.Timeout = chartSpec.Timeout
 	upgradeOptions.Wait = chartSpec.Wait
@@ -1,12 +1,16 @@
 package helmclient
 
 import (
+	"sync"
+
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
 // NewRESTClientGetter returns a RESTClientGetter using the provided 'namespace', 'kubeConfig' and 'restConfig'.
@@ -73,3 +77,93 @@ func (c *RESTClientGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
 
 	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
 }
+
+// sharedRESTClientGetter wraps a RESTClientGetter, creating its discovery client and REST mapper only once,
+// so that they are shared by all clients derived via WithNamespace. The namespace of the kubeconfig can be overridden.
+type sharedRESTClientGetter struct {
+	genericclioptions.RESTClientGetter
+	namespace string
+	cache     *discoveryCache
+}
+
+// discoveryCache holds the discovery client and REST mapper of a sharedRESTClientGetter.
+// Failures are not cached, so that a transient error is retried on the next call.
+type discoveryCache struct {
+	mu              sync.Mutex
+	discoveryClient discovery.CachedDiscoveryInterface
+	mapper          meta.RESTMapper
+}
+
+// newSharedRESTClientGetter returns a sharedRESTClientGetter wrapping the provided getter.
+func newSharedRESTClientGetter(getter genericclioptions.RESTClientGetter) *sharedRESTClientGetter {
+	if shared, ok := getter.(*sharedRESTClientGetter); ok {
+		return shared
+	}
+
+	return &sharedRESTClientGetter{RESTClientGetter: getter, cache: &discoveryCache{}}
+}
+
+// withNamespace returns a getter for the provided namespace sharing the discovery cache.
+func (g *sharedRESTClientGetter) withNamespace(namespace string) *sharedRESTClientGetter {
+	return &sharedRESTClientGetter{RESTClientGetter: g.RESTClientGetter, namespace: namespace, cache: g.cache}
+}
+
+func (g *sharedRESTClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	discoveryClient, _, err := g.init()
+	return discoveryClient, err
+}
+
+func (g *sharedRESTClientGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	_, mapper, err := g.init()
+	return mapper, err
+}
+
+func (g *sharedRESTClientGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
+	loader := g.RESTClientGetter.ToRawKubeConfigLoader()
+	if g.namespace == "" {
+		return loader
+	}
+
+	return &namespacedClientConfig{loader: loader, namespace: g.namespace}
+}
+
+// init returns the discovery client and REST mapper of the wrapped getter, creating them on first success.
+func (g *sharedRESTClientGetter) init() (discovery.CachedDiscoveryInterface, meta.RESTMapper, error) {
+	g.cache.mu.Lock()
+	defer g.cache.mu.Unlock()
+
+	if g.cache.discoveryClient == nil {
+		discoveryClient, err := g.RESTClientGetter.ToDiscoveryClient()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		mapper := restmapper.NewDeferredDiscoveryRESTMapper(discoveryClient)
+		g.cache.discoveryClient = discoveryClient
+		g.cache.mapper = restmapper.NewShortcutExpander(mapper, discoveryClient, nil)
+	}
+
+	return g.cache.discoveryClient, g.cache.mapper, nil
+}
+
+// namespacedClientConfig is a kubeconfig whose namespace is overridden.
+type namespacedClientConfig struct {
+	loader    clientcmd.ClientConfig
+	namespace string
+}
+
+func (c *namespacedClientConfig) RawConfig() (clientcmdapi.Config, error) {
+	return c.loader.RawConfig()
+}
+
+func (c *namespacedClientConfig) ClientConfig() (*rest.Config, error) {
+	return c.loader.ClientConfig()
+}
+
+func (c *namespacedClientConfig) Namespace() (string, bool, error) {
+	return c.namespace, true, nil
+}
+
+func (c *namespacedClientConfig) ConfigAccess() clientcmd.ConfigAccess {
+	return c.loader.ConfigAccess()
+}
@@ -3,13 +3,29 @@ package helmclient
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
 	"time"
 
 	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/getter"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
 
 	"helm.sh/helm/v3/pkg/action"
 
 	"helm.sh/helm/v3/pkg/repo"
+	helmstorage "helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
 )
 
@@ -255,6 +271,38 @@ func ExampleHelmClient_LintChart() {
 	}
 }
 
+func ExampleHelmClient_LintChartWithOptions() {
+	// Define a chart with custom values to be tested.
+	chartSpec := ChartSpec{
+		ReleaseName: "etcd-operator",
+		ChartName:   "stable/etcd-operator",
+		Namespace:   "default",
+		ValuesYaml: `deployments:
+  etcdOperator: true
+  backupOperator: false`,
+	}
+
+	// Fail on warnings and render the chart for a specific Kubernetes version.
+	options := &LintOptions{
+		Strict: true,
+		KubeVersion: &chartutil.KubeVersion{
+			Version: "v1.23.10",
+			Major:   "1",
+			Minor:   "23",
+		},
+	}
+
+	report, err := helmClient.LintChartWithOptions(context.Background(), &chartSpec, options)
+	if report != nil {
+		for _, msg := range report.Messages {
+			fmt.Println(msg)
+		}
+	}
+	if err != nil {
+		panic(err)
+	}
+}
+
 func ExampleHelmClient_TemplateChart() {
 	chartSpec := ChartSpec{
 		ReleaseName: "etcd-operator",
@@ -355,3 +403,268 @@ func ExampleHelmClient_RollbackRelease() {
 		return
 	}
 }
+
+func ExampleHelmClient_RollbackToRevision() {
+	// Find the latest revision that was deployed successfully, skipping failed ones.
+	revision, err := helmClient.LastSuccessfulRevision(context.Background(), "etcd-operator")
+	if err != nil {
+		panic(err)
+	}
+
+	// Rollback to that revision and wait for its resources to become ready.
+	if _, err := helmClient.RollbackToRevision(context.Background(), "etcd-operator", revision, &RollbackOptions{Wait: true, Timeout: time.Minute}); err != nil {
+		panic(err)
+	}
+}
+
+func ExampleHelmClient_UninstallReleaseByNameWithContext() {
+	// Bound the uninstallation, including waiting for hooks, to one minute.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// Uninstall a release by name.
+	if err := helmClient.UninstallReleaseByNameWithContext(ctx, "etcd-operator"); err != nil {
+		panic(err)
+	}
+}
+
+func ExampleHelmClient_DiffChart() {
+	// Define the chart to be upgraded.
+	chartSpec := ChartSpec{
+		ReleaseName: "etcd-operator",
+		ChartName:   "stable/etcd-operator",
+		Namespace:   "default",
+		ValuesYaml: `deployments:
+  backupOperator: true`,
+	}
+
+	// Show what an upgrade of the release would change.
+	diff, err := helmClient.DiffChart(context.Background(), &chartSpec)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, resource := range diff.Changed {
+		fmt.Print(resource.Diff)
+	}
+}
+
+func TestNewClientStorageDriver(t *testing.T) {
+	newClient := func(t *testing.T, options *Options) (*HelmClient, error) {
+		t.Helper()
+
+		options.RepositoryConfig = filepath.Join(t.TempDir(), "repositories.yaml")
+		options.RepositoryCache = t.TempDir()
+		options.RegistryConfig = filepath.Join(t.TempDir(), "config.json")
+		options.DebugLog = func(string, ...interface{}) {}
+
+		c, err := NewClientFromRestConf(&RestConfClientOptions{Options: options, RestConfig: &rest.Config{Host: "https://127.0.0.1:6443"}})
+		if err != nil {
+			return nil, err
+		}
+
+		return c.(*HelmClient), nil
+	}
+
+	t.Setenv("HELM_DRIVER", StorageDriverConfigMap)
+
+	c, err := newClient(t, &Options{Namespace: "default"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if name := c.ActionConfig.Releases.Name(); name != driver.ConfigMapsDriverName {
+		t.Errorf("expected the driver of HELM_DRIVER to be used, got %q", name)
+	}
+
+	c, err = newClient(t, &Options{Namespace: "default", StorageDriver: StorageDriverMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if name := c.ActionConfig.Releases.Name(); name != driver.MemoryDriverName {
+		t.Errorf("expected the memory driver, got %q", name)
+	}
+
+	custom := driver.NewMemory()
+	c, err = newClient(t, &Options{Namespace: "default", StorageDriver: StorageDriverSecret, Driver: custom})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	derived, err := c.WithNamespace("other")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, client := range []*HelmClient{c, derived.(*HelmClient)} {
+		if client.ActionConfig.Releases.Driver != custom {
+			t.Errorf("expected the custom driver to be used, got %T", client.ActionConfig.Releases.Driver)
+		}
+	}
+
+	if _, err := newClient(t, &Options{Namespace: "default", StorageDriver: "etcd"}); err == nil {
+		t.Error("expected an error for an unknown driver")
+	}
+}
+
+func TestWithNamespace(t *testing.T) {
+	c := newTestClient(t)
+
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind": "SecretList", "apiVersion": "v1", "items": []}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c.ActionConfig.RESTClientGetter = newSharedRESTClientGetter(&testRESTClientGetter{config: &rest.Config{Host: server.URL}})
+	// Store the releases of derived clients in secrets, so that listing them requests the cluster.
+	c.releaseStorage = releaseStorageOptions{driverName: StorageDriverSecret}
+
+	derived, err := c.WithNamespace("other")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, ok := derived.(*HelmClient)
+	if !ok {
+		t.Fatalf("expected a *HelmClient, got %T", derived)
+	}
+
+	if other.Settings.Namespace() != "other" {
+		t.Errorf("expected the namespace 'other', got %q", other.Settings.Namespace())
+	}
+
+	if c.Settings.Namespace() == "other" {
+		t.Error("expected the namespace of the original client to be unchanged")
+	}
+
+	if other.Settings.RepositoryConfig != c.Settings.RepositoryConfig || other.storage != c.storage ||
+		other.registryCredentials != c.registryCredentials || other.ActionConfig.RegistryClient != c.ActionConfig.RegistryClient {
+		t.Error("expected the repositories and registry settings to be shared")
+	}
+
+	if _, err := other.ListDeployedReleases(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Contains(paths, "/api/v1/namespaces/other/secrets") {
+		t.Errorf("expected the releases to be listed in the namespace 'other', got requests to %v", paths)
+	}
+
+	again, err := derived.WithNamespace("again")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shared := c.ActionConfig.RESTClientGetter.(*sharedRESTClientGetter)
+	for _, client := range []Client{derived, again} {
+		getter := client.(*HelmClient).ActionConfig.RESTClientGetter.(*sharedRESTClientGetter)
+		if getter.cache != shared.cache {
+			t.Error("expected the discovery cache to be shared")
+		}
+	}
+}
+
+// flakyRESTClientGetter is a RESTClientGetter whose first discovery client cannot be created.
+type flakyRESTClientGetter struct {
+	*RESTClientGetter
+	calls int
+}
+
+func (g *flakyRESTClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	g.calls++
+	if g.calls == 1 {
+		return nil, errors.New("transient failure")
+	}
+
+	return g.RESTClientGetter.ToDiscoveryClient()
+}
+
+func TestSharedRESTClientGetterRetriesFailures(t *testing.T) {
+	flaky := &flakyRESTClientGetter{RESTClientGetter: NewRESTClientGetter("default", nil, &rest.Config{Host: "https://127.0.0.1:6443"})}
+	shared := newSharedRESTClientGetter(flaky)
+	derived := shared.withNamespace("other")
+
+	if _, err := shared.ToDiscoveryClient(); err == nil {
+		t.Fatal("expected the first discovery client to fail")
+	}
+
+	mapper, err := derived.ToRESTMapper()
+	if err != nil || mapper == nil {
+		t.Fatalf("expected the failure not to be cached, got %v", err)
+	}
+
+	discoveryClient, err := shared.ToDiscoveryClient()
+	if err != nil || discoveryClient == nil {
+		t.Fatal(err)
+	}
+
+	if flaky.calls != 2 {
+		t.Errorf("expected the successful discovery client to be cached, got %d calls", flaky.calls)
+	}
+}
+
+// newTestClient returns a HelmClient which is not connected to any cluster.
+func newTestClient(t *testing.T) *HelmClient {
+	t.Helper()
+
+	settings := cli.New()
+	settings.RepositoryConfig = filepath.Join(t.TempDir(), "repositories.yaml")
+	settings.RepositoryCache = t.TempDir()
+	settings.RegistryConfig = filepath.Join(t.TempDir(), "config.json")
+
+	storage, err := loadRepositoryStorage(settings.RepositoryConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &HelmClient{
+		Settings:            settings,
+		Providers:           getter.All(settings),
+		storage:             storage,
+		registryCredentials: newRegistryCredentials(settings.RegistryConfig),
+		releaseStorage:      releaseStorageOptions{driverName: StorageDriverMemory, memory: newMemoryReleases()},
+		DebugLog:            func(string, ...interface{}) {},
+		output:              io.Discard,
+	}
+
+	registryClient, err := c.newRegistryClient(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.ActionConfig = &action.Configuration{
+		Releases:       helmstorage.Init(c.releaseStorage.memory.driver(settings.Namespace())),
+		KubeClient:     &kubefake.PrintingKubeClient{Out: io.Discard},
+		Capabilities:   chartutil.DefaultCapabilities,
+		RegistryClient: registryClient,
+		Log:            c.DebugLog,
+	}
+
+	return c
+}
+
+// writeTestChart writes the provided files into a new chart directory and returns its path.
+func writeTestChart(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	chartPath := filepath.Join(t.TempDir(), "test-chart")
+	for name, content := range files {
+		path := filepath.Join(chartPath, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return chartPath
+}
@@ -0,0 +1,97 @@
+package helmclient
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"helm.sh/helm/v3/pkg/getter"
+)
+
+// runWithContext runs fn and returns as soon as either fn completes or ctx is done.
+// Helm actions that do not accept a context are run this way, similar to
+// action.Install.RunWithContext, so that callers are not blocked beyond their deadline.
+func runWithContext[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	if err := ctx.Err(); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+
+	resultChan := make(chan result, 1)
+	go func() {
+		value, err := fn()
+		resultChan <- result{value: value, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	case r := <-resultChan:
+		return r.value, r.err
+	}
+}
+
+// timeoutWithContext returns the provided timeout, capped at the time remaining until the deadline of ctx.
+// A zero timeout is treated as unbounded.
+func timeoutWithContext(ctx context.Context, timeout time.Duration) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return timeout
+	}
+
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		// A non-positive timeout would disable waiting entirely, so use the smallest possible one instead.
+		return time.Nanosecond
+	}
+
+	if timeout == 0 || remaining < timeout {
+		return remaining
+	}
+
+	return timeout
+}
+
+// contextProviders wraps the provided getter providers, so that every download is bound to ctx.
+func contextProviders(ctx context.Context, providers getter.Providers) getter.Providers {
+	wrapped := make(getter.Providers, 0, len(providers))
+	for _, p := range providers {
+		newGetter := p.New
+		wrapped = append(wrapped, getter.Provider{
+			Schemes: p.Schemes,
+			New: func(options ...getter.Option) (getter.Getter, error) {
+				g, err := newGetter(options...)
+				if err != nil {
+					return nil, err
+				}
+
+				return &contextGetter{ctx: ctx, getter: g}, nil
+			},
+		})
+	}
+
+	return wrapped
+}
+
+// contextGetter is a getter.Getter whose downloads are cancelled with its context.
+type contextGetter struct {
+	ctx    context.Context
+	getter getter.Getter
+}
+
+// Get downloads the provided URL, bounding the download by the deadline of the getter's context.
+func (g *contextGetter) Get(url string, options ...getter.Option) (*bytes.Buffer, error) {
+	if _, ok := g.ctx.Deadline(); ok {
+		options = append(options, getter.WithTimeout(timeoutWithContext(g.ctx, 0)))
+	}
+
+	return runWithContext(g.ctx, func() (*bytes.Buffer, error) {
+		return g.getter.Get(url, options...)
+	})
+}
@@ -0,0 +1,52 @@
+package helmclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTimeoutWithContext(t *testing.T) {
+	if got := timeoutWithContext(context.Background(), time.Minute); got != time.Minute {
+		t.Errorf("expected timeout without deadline to be kept, got %s", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if got := timeoutWithContext(ctx, time.Hour); got > time.Minute {
+		t.Errorf("expected timeout to be capped at the deadline, got %s", got)
+	}
+
+	if got := timeoutWithContext(ctx, 0); got <= 0 || got > time.Minute {
+		t.Errorf("expected zero timeout to be replaced by the remaining time, got %s", got)
+	}
+
+	if got := timeoutWithContext(ctx, time.Second); got != time.Second {
+		t.Errorf("expected shorter timeout to be kept, got %s", got)
+	}
+}
+
+func TestRunWithContext(t *testing.T) {
+	value, err := runWithContext(context.Background(), func() (int, error) {
+		return 42, nil
+	})
+	if err != nil || value != 42 {
+		t.Fatalf("expected 42 and no error, got %d and %v", value, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	block := make(chan struct{})
+	defer close(block)
+
+	go cancel()
+
+	_, err = runWithContext(ctx, func() (int, error) {
+		<-block
+		return 0, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,528 @@
+package helmclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// diffContextLines is the number of unchanged lines surrounding each hunk of a unified diff.
+const diffContextLines = 3
+
+// DiffChart renders the provided ChartSpec 'spec' the same way an upgrade would and compares the result
+// with the manifest of the currently deployed release. If the release does not exist yet, the chart is
+// rendered as it would be installed and every resource is reported as added.
+// Hooks are not part of the comparison. Note that the diffs of Secret resources contain their data.
+func (c *HelmClient) DiffChart(ctx context.Context, spec *ChartSpec) (*ReleaseDiff, error) {
+	return c.DiffChartWithOptions(ctx, spec, nil)
+}
+
+// DiffChartWithOptions compares the provided ChartSpec 'spec' with the deployed release like DiffChart,
+// rendering the chart with the provided GenericHelmOptions 'opts', e.g. to apply the post renderer of the upgrade.
+func (c *HelmClient) DiffChartWithOptions(ctx context.Context, spec *ChartSpec, opts *GenericHelmOptions) (*ReleaseDiff, error) {
+	current, err := c.getRelease(ctx, spec.ReleaseName)
+	if err != nil && !errors.Is(err, ErrReleaseNotFound) {
+		return nil, err
+	}
+
+	var target *release.Release
+	if current == nil {
+		target, err = c.renderInstall(ctx, spec, opts)
+	} else {
+		target, err = c.renderUpgrade(ctx, spec, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReleaseDiff{
+		ReleaseName: spec.ReleaseName,
+		Namespace:   spec.Namespace,
+		Installed:   current != nil,
+	}
+
+	var currentManifest string
+	var currentValues map[string]interface{}
+	if current != nil {
+		currentManifest = current.Manifest
+		currentValues = current.Config
+	}
+
+	result.Added, result.Removed, result.Changed, err = diffManifests(currentManifest, target.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Values = diffValues(currentValues, target.Config)
+
+	return result, nil
+}
+
+// renderInstall renders the provided ChartSpec 'spec' by performing a "dry-run" install.
+func (c *HelmClient) renderInstall(ctx context.Context, spec *ChartSpec, opts *GenericHelmOptions) (*release.Release, error) {
+	actionConfig, err := c.actionConfig(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	client := action.NewInstall(actionConfig)
+	mergeInstallOptions(spec, client)
+
+	client.DryRun = true
+
+	// NameAndChart returns either the TemplateName if set,
+	// the ReleaseName if set or the generatedName as the first return value.
+	releaseName, _, err := client.NameAndChart([]string{spec.ChartName})
+	if err != nil {
+		return nil, err
+	}
+	client.ReleaseName = releaseName
+
+	if client.Version == "" {
+		client.Version = ">0.0.0-0"
+	}
+
+	if opts != nil && opts.PostRenderer != nil {
+		client.PostRenderer = opts.PostRenderer
+	}
+
+	helmChart, _, values, err := c.loadChartAndValues(ctx, spec, &client.ChartPathOptions, client.DependencyUpdate, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.RunWithContext(ctx, helmChart, values)
+}
+
+// renderUpgrade renders the provided ChartSpec 'spec' by performing a "dry-run" upgrade.
+func (c *HelmClient) renderUpgrade(ctx context.Context, spec *ChartSpec, opts *GenericHelmOptions) (*release.Release, error) {
+	actionConfig, err := c.actionConfig(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	client := action.NewUpgrade(actionConfig)
+	mergeUpgradeOptions(spec, client)
+
+	client.DryRun = true
+
+	if client.Version == "" {
+		client.Version = ">0.0.0-0"
+	}
+
+	if opts != nil && opts.PostRenderer != nil {
+		client.PostRenderer = opts.PostRenderer
+	}
+
+	helmChart, _, values, err := c.loadChartAndValues(ctx, spec, &client.ChartPathOptions, client.DependencyUpdate, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.RunWithContext(ctx, spec.ReleaseName, helmChart, values)
+}
+
+// manifestResource is a single Kubernetes resource parsed from a release manifest.
+type manifestResource struct {
+	object *unstructured.Unstructured
+	// yaml is the normalized YAML representation of object.
+	yaml string
+}
+
+// resourceKey identifies a resource independently of its API version.
+type resourceKey struct {
+	groupKind schema.GroupKind
+	namespace string
+	name      string
+}
+
+// parseManifest splits the provided release manifest into its resources.
+// Documents that do not describe a Kubernetes resource are ignored.
+func parseManifest(manifest string) (map[resourceKey]*manifestResource, error) {
+	resources := map[resourceKey]*manifestResource{}
+
+	for _, doc := range releaseutil.SplitManifests(manifest) {
+		var content map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &content); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest: %w", err)
+		}
+
+		if len(content) == 0 {
+			continue
+		}
+
+		object := &unstructured.Unstructured{Object: content}
+		if object.GetKind() == "" || object.GetName() == "" {
+			continue
+		}
+
+		normalized, err := yaml.Marshal(content)
+		if err != nil {
+			return nil, err
+		}
+
+		key := resourceKey{
+			groupKind: object.GroupVersionKind().GroupKind(),
+			namespace: object.GetNamespace(),
+			name:      object.GetName(),
+		}
+
+		resources[key] = &manifestResource{object: object, yaml: string(normalized)}
+	}
+
+	return resources, nil
+}
+
+// diffManifests compares two release manifests resource by resource.
+func diffManifests(from, to string) (added, removed, changed []ResourceDiff, err error) {
+	fromResources, err := parseManifest(from)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	toResources, err := parseManifest(to)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	for key, toResource := range toResources {
+		fromResource, ok := fromResources[key]
+		if !ok {
+			added = append(added, newResourceDiff(toResource.object, "", toResource.yaml))
+			continue
+		}
+
+		if fromResource.yaml != toResource.yaml {
+			changed = append(changed, newResourceDiff(toResource.object, fromResource.yaml, toResource.yaml))
+		}
+	}
+
+	for key, fromResource := range fromResources {
+		if _, ok := toResources[key]; !ok {
+			removed = append(removed, newResourceDiff(fromResource.object, fromResource.yaml, ""))
+		}
+	}
+
+	sortResourceDiffs(added)
+	sortResourceDiffs(removed)
+	sortResourceDiffs(changed)
+
+	return added, removed, changed, nil
+}
+
+// newResourceDiff returns a ResourceDiff of the provided object, diffing 'from' against 'to'.
+func newResourceDiff(object *unstructured.Unstructured, from, to string) ResourceDiff {
+	d := ResourceDiff{
+		APIVersion: object.GetAPIVersion(),
+		Kind:       object.GetKind(),
+		Namespace:  object.GetNamespace(),
+		Name:       object.GetName(),
+	}
+	d.Diff = unifiedDiff(d.String(), from, to)
+
+	return d
+}
+
+// sortResourceDiffs sorts the provided diffs by namespace, kind and name.
+func sortResourceDiffs(diffs []ResourceDiff) {
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Namespace != diffs[j].Namespace {
+			return diffs[i].Namespace < diffs[j].Namespace
+		}
+		if diffs[i].Kind != diffs[j].Kind {
+			return diffs[i].Kind < diffs[j].Kind
+		}
+		return diffs[i].Name < diffs[j].Name
+	})
+}
+
+// diffValues compares two sets of release values, reporting changes per leaf value.
+func diffValues(from, to map[string]interface{}) []ValueChange {
+	fromValues := map[string]interface{}{}
+	flattenValues("", from, fromValues)
+
+	toValues := map[string]interface{}{}
+	flattenValues("", to, toValues)
+
+	var changes []ValueChange
+
+	for path, newValue := range toValues {
+		oldValue, ok := fromValues[path]
+		switch {
+		case !ok:
+			changes = append(changes, ValueChange{Path: path, Type: DiffAdded, New: newValue})
+		case !reflect.DeepEqual(oldValue, newValue):
+			changes = append(changes, ValueChange{Path: path, Type: DiffChanged, Old: oldValue, New: newValue})
+		}
+	}
+
+	for path, oldValue := range fromValues {
+		if _, ok := toValues[path]; !ok {
+			changes = append(changes, ValueChange{Path: path, Type: DiffRemoved, Old: oldValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes
+}
+
+// flattenValues stores every leaf of the provided values in 'out', keyed by its dot-separated path.
+func flattenValues(prefix string, values map[string]interface{}, out map[string]interface{}) {
+	for key, value := range values {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenValues(path, nested, out)
+			continue
+		}
+
+		out[path] = value
+	}
+}
+
+// diffOperation is a single line operation of a line based diff.
+type diffOperation struct {
+	kind byte // ' ', '-' or '+'
+	line string
+}
+
+// unifiedDiff returns a unified diff of 'from' and 'to', which is empty if both are equal.
+func unifiedDiff(name, from, to string) string {
+	operations := diffLines(splitLines(from), splitLines(to))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", name, name)
+	hasHunks := false
+
+	for start := 0; start < len(operations); {
+		// Skip to the next change.
+		for start < len(operations) && operations[start].kind == ' ' {
+			start++
+		}
+		if start == len(operations) {
+			break
+		}
+
+		// Extend the hunk as long as changes are at most two context windows apart.
+		end := start
+		for i := start; i < len(operations); i++ {
+			if operations[i].kind != ' ' {
+				end = i + 1
+			} else if i-end >= 2*diffContextLines {
+				break
+			}
+		}
+
+		hunkStart := max(start-diffContextLines, 0)
+		hunkEnd := min(end+diffContextLines, len(operations))
+
+		fromLine, toLine := 1, 1
+		for _, op := range operations[:hunkStart] {
+			if op.kind != '+' {
+				fromLine++
+			}
+			if op.kind != '-' {
+				toLine++
+			}
+		}
+
+		fromCount, toCount := 0, 0
+		for _, op := range operations[hunkStart:hunkEnd] {
+			if op.kind != '+' {
+				fromCount++
+			}
+			if op.kind != '-' {
+				toCount++
+			}
+		}
+
+		// Following the unified diff format, empty ranges start at the line before the hunk.
+		if fromCount == 0 {
+			fromLine--
+		}
+		if toCount == 0 {
+			toLine--
+		}
+
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", fromLine, fromCount, toLine, toCount)
+		for _, op := range operations[hunkStart:hunkEnd] {
+			fmt.Fprintf(&out, "%c%s\n", op.kind, op.line)
+		}
+
+		hasHunks = true
+		start = hunkEnd
+	}
+
+	if !hasHunks {
+		return ""
+	}
+
+	return out.String()
+}
+
+// splitLines splits the provided text into lines, ignoring a trailing newline.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines computes a minimal line based diff of 'a' and 'b' using the linear space variant of the Myers algorithm,
+// which recursively splits both inputs at a point of an optimal edit path instead of recording the whole search.
+func diffLines(a, b []string) []diffOperation {
+	return appendLineDiff(make([]diffOperation, 0, len(a)+len(b)), a, b)
+}
+
+// appendLineDiff appends the operations transforming 'a' into 'b' to 'operations'.
+func appendLineDiff(operations []diffOperation, a, b []string) []diffOperation {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	for _, line := range a[:prefix] {
+		operations = append(operations, diffOperation{kind: ' ', line: line})
+	}
+	a, b = a[prefix:], b[prefix:]
+
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0:
+		for _, line := range b {
+			operations = append(operations, diffOperation{kind: '+', line: line})
+		}
+	case len(b) == 0:
+		for _, line := range a {
+			operations = append(operations, diffOperation{kind: '-', line: line})
+		}
+	default:
+		x, y := splitLineDiff(a, b)
+		operations = appendLineDiff(operations, a[:x], b[:y])
+		operations = appendLineDiff(operations, a[x:], b[y:])
+	}
+
+	for _, line := range common {
+		operations = append(operations, diffOperation{kind: ' ', line: line})
+	}
+
+	return operations
+}
+
+// splitLineDiff returns a point (x, y) on an optimal edit path from 'a' to 'b', found by searching forward
+// from the start and backward from the end until both searches overlap. Both inputs must not be empty.
+func splitLineDiff(a, b []string) (int, int) {
+	n, m := len(a), len(b)
+	maxD := (n + m + 1) / 2
+	offset := maxD
+
+	// forward[offset+k] and backward[offset+k] are the furthest x reached on diagonal k, or -1 if not reached yet.
+	forward := make([]int, 2*maxD+2)
+	backward := make([]int, 2*maxD+2)
+	for i := range forward {
+		forward[i] = -1
+		backward[i] = -1
+	}
+	forward[offset+1] = 0
+	backward[offset+1] = 0
+
+	delta := n - m
+	// If the difference of the lengths is odd, the forward search detects the overlap, otherwise the backward search.
+	forwardOverlap := delta%2 != 0
+
+	// The number of diagonals to skip at the lower and upper end because they left the edit graph.
+	forwardStart, forwardEnd, backwardStart, backwardEnd := 0, 0, 0, 0
+
+	for d := 0; d < maxD; d++ {
+		for k := -d + forwardStart; k <= d-forwardEnd; k += 2 {
+			i := offset + k
+
+			var x int
+			if k == -d || (k != d && forward[i-1] < forward[i+1]) {
+				x = forward[i+1]
+			} else {
+				x = forward[i-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[i] = x
+
+			switch {
+			case x > n:
+				forwardEnd += 2
+			case y > m:
+				forwardStart += 2
+			case forwardOverlap:
+				j := offset + delta - k
+				if j >= 0 && j < len(backward) && backward[j] != -1 && x >= n-backward[j] {
+					return x, y
+				}
+			}
+		}
+
+		for k := -d + backwardStart; k <= d-backwardEnd; k += 2 {
+			i := offset + k
+
+			var x int
+			if k == -d || (k != d && backward[i-1] < backward[i+1]) {
+				x = backward[i+1]
+			} else {
+				x = backward[i-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[n-x-1] == b[m-y-1] {
+				x++
+				y++
+			}
+			backward[i] = x
+
+			switch {
+			case x > n:
+				backwardEnd += 2
+			case y > m:
+				backwardStart += 2
+			case !forwardOverlap:
+				j := offset + delta - k
+				if j >= 0 && j < len(forward) && forward[j] != -1 {
+					forwardX := forward[j]
+					forwardY := offset + forwardX - j
+					if forwardX >= n-x {
+						return forwardX, forwardY
+					}
+				}
+			}
+		}
+	}
+
+	// The inputs have no line in common, so every line of 'a' is removed and every line of 'b' added.
+	return n, 0
+}
@@ -0,0 +1,241 @@
+package helmclient
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+const currentManifest = `---
+# Source: app/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  a: "1"
+  b: "2"
+---
+# Source: app/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+spec:
+  ports:
+  - port: 80
+`
+
+const targetManifest = `---
+# Source: app/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  a: "1"
+  b: "3"
+---
+# Source: app/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+`
+
+func TestDiffManifests(t *testing.T) {
+	added, removed, changed, err := diffManifests(currentManifest, targetManifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(added) != 1 || added[0].Kind != "Deployment" {
+		t.Errorf("expected the Deployment to be added, got %v", added)
+	}
+
+	if len(removed) != 1 || removed[0].Kind != "Service" {
+		t.Errorf("expected the Service to be removed, got %v", removed)
+	}
+
+	if len(changed) != 1 || changed[0].Kind != "ConfigMap" {
+		t.Fatalf("expected the ConfigMap to be changed, got %v", changed)
+	}
+
+	expectedDiff := `--- ConfigMap/app-config (v1)
++++ ConfigMap/app-config (v1)
+@@ -1,7 +1,7 @@
+ apiVersion: v1
+ data:
+   a: "1"
+-  b: "2"
++  b: "3"
+ kind: ConfigMap
+ metadata:
+   name: app-config
+`
+	if changed[0].Diff != expectedDiff {
+		t.Errorf("unexpected diff:\n%s", changed[0].Diff)
+	}
+}
+
+func TestDiffManifestsUnchanged(t *testing.T) {
+	added, removed, changed, err := diffManifests(currentManifest, currentManifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(added)+len(removed)+len(changed) != 0 {
+		t.Errorf("expected no changes, got added=%v removed=%v changed=%v", added, removed, changed)
+	}
+}
+
+func TestUnifiedDiffHunks(t *testing.T) {
+	from := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	to := "0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n12\n"
+
+	expected := `--- test
++++ test
+@@ -1,3 +1,4 @@
++0
+ 1
+ 2
+ 3
+@@ -8,5 +9,4 @@
+ 8
+ 9
+ 10
+-11
+ 12
+`
+	if got := unifiedDiff("test", from, to); got != expected {
+		t.Errorf("unexpected diff:\n%s", got)
+	}
+
+	if got := unifiedDiff("test", from, from); got != "" {
+		t.Errorf("expected empty diff for equal input, got:\n%s", got)
+	}
+}
+
+func TestDiffLinesMinimal(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	randomLines := func() []string {
+		lines := make([]string, random.Intn(12))
+		for i := range lines {
+			lines[i] = strconv.Itoa(random.Intn(4))
+		}
+		return lines
+	}
+
+	for i := 0; i < 1000; i++ {
+		a, b := randomLines(), randomLines()
+
+		operations := diffLines(a, b)
+		assertDiffOperations(t, a, b, operations)
+
+		// The number of edits of a minimal diff is determined by the longest common subsequence.
+		lcs := make([][]int, len(a)+1)
+		for x := range lcs {
+			lcs[x] = make([]int, len(b)+1)
+		}
+		for x := len(a) - 1; x >= 0; x-- {
+			for y := len(b) - 1; y >= 0; y-- {
+				if a[x] == b[y] {
+					lcs[x][y] = lcs[x+1][y+1] + 1
+				} else {
+					lcs[x][y] = max(lcs[x+1][y], lcs[x][y+1])
+				}
+			}
+		}
+
+		if edits := countEdits(operations); edits != len(a)+len(b)-2*lcs[0][0] {
+			t.Fatalf("expected a minimal diff of %v and %v, got %d edits", a, b, edits)
+		}
+	}
+}
+
+func TestDiffLinesLarge(t *testing.T) {
+	// A config map with 20000 data lines, every tenth of which is changed.
+	from := make([]string, 20000)
+	to := make([]string, len(from))
+	for i := range from {
+		from[i] = fmt.Sprintf("  key-%d: value-%d", i, i)
+		to[i] = from[i]
+		if i%10 == 0 {
+			to[i] = fmt.Sprintf("  key-%d: changed-%d", i, i)
+		}
+	}
+
+	operations := diffLines(from, to)
+	assertDiffOperations(t, from, to, operations)
+
+	if edits := countEdits(operations); edits != len(from)/10*2 {
+		t.Errorf("expected %d edits, got %d", len(from)/10*2, edits)
+	}
+}
+
+// assertDiffOperations asserts that the provided operations transform 'a' into 'b'.
+func assertDiffOperations(t *testing.T, a, b []string, operations []diffOperation) {
+	t.Helper()
+
+	var gotA, gotB []string
+	for _, op := range operations {
+		if op.kind != '+' {
+			gotA = append(gotA, op.line)
+		}
+		if op.kind != '-' {
+			gotB = append(gotB, op.line)
+		}
+	}
+
+	if !slices.Equal(gotA, a) || !slices.Equal(gotB, b) {
+		t.Fatalf("expected the operations to transform %v into %v, got %v", a, b, operations)
+	}
+}
+
+// countEdits returns the number of added and removed lines of the provided operations.
+func countEdits(operations []diffOperation) int {
+	edits := 0
+	for _, op := range operations {
+		if op.kind != ' ' {
+			edits++
+		}
+	}
+
+	return edits
+}
+
+func TestDiffValues(t *testing.T) {
+	from := map[string]interface{}{
+		"image": map[string]interface{}{
+			"tag": "1.0",
+		},
+		"replicas": 1,
+	}
+	to := map[string]interface{}{
+		"image": map[string]interface{}{
+			"tag": "1.1",
+		},
+		"debug": true,
+	}
+
+	changes := diffValues(from, to)
+	expected := []ValueChange{
+		{Path: "debug", Type: DiffAdded, New: true},
+		{Path: "image.tag", Type: DiffChanged, Old: "1.0", New: "1.1"},
+		{Path: "replicas", Type: DiffRemoved, Old: 1},
+	}
+
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %v", len(expected), changes)
+	}
+
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], changes[i])
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package helmclient
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// DetectDrift compares the resources of the latest revision of the release with the provided name against their live state,
+// fetched via the client's RESTClientGetter. It reports resources that were deleted and fields of the manifest
+// whose live values were modified out-of-band, e.g. by 'kubectl edit'.
+// Fields that are not part of the manifest, e.g. those defaulted by the API server, and the status are ignored.
+// Fields of the manifest set to their zero value are considered unchanged if the API server omits them.
+// The write-only stringData of secrets is compared with their encoded data.
+func (c *HelmClient) DetectDrift(ctx context.Context, name string) (*ReleaseDrift, error) {
+	rel, err := c.getRelease(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := parseManifest(rel.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	restConfig, err := c.ActionConfig.RESTClientGetter.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	mapper, err := c.ActionConfig.RESTClientGetter.ToRESTMapper()
+	if err != nil {
+		return nil, err
+	}
+
+	drift := &ReleaseDrift{ReleaseName: rel.Name, Namespace: rel.Namespace, Revision: rel.Version}
+
+	for _, resource := range resources {
+		resourceDrift, err := detectResourceDrift(ctx, dynamicClient, mapper, resource.object, rel.Namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		if resourceDrift != nil {
+			drift.Resources = append(drift.Resources, *resourceDrift)
+		}
+	}
+
+	sort.Slice(drift.Resources, func(i, j int) bool {
+		a, b := drift.Resources[i], drift.Resources[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+
+	return drift, nil
+}
+
+// detectResourceDrift compares the provided manifest object with its live state.
+// It returns nil if the resource has not drifted.
+func detectResourceDrift(ctx context.Context, dynamicClient dynamic.Interface, mapper meta.RESTMapper, object *unstructured.Unstructured, releaseNamespace string) (*ResourceDrift, error) {
+	gvk := object.GroupVersionKind()
+	resourceDrift := &ResourceDrift{
+		APIVersion: object.GetAPIVersion(),
+		Kind:       object.GetKind(),
+		Namespace:  object.GetNamespace(),
+		Name:       object.GetName(),
+	}
+
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		// The resource type is not served anymore, e.g. because its CRD was deleted.
+		resourceDrift.Deleted = true
+		return resourceDrift, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to map %s: %w", resourceDrift, err)
+	}
+
+	namespaceableClient := dynamicClient.Resource(mapping.Resource)
+
+	var resourceClient dynamic.ResourceInterface = namespaceableClient
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		if resourceDrift.Namespace == "" {
+			resourceDrift.Namespace = releaseNamespace
+		}
+		resourceClient = namespaceableClient.Namespace(resourceDrift.Namespace)
+	}
+
+	live, err := resourceClient.Get(ctx, resourceDrift.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		resourceDrift.Deleted = true
+		return resourceDrift, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", resourceDrift, err)
+	}
+
+	for key, expected := range foldSecretStringData(object) {
+		if key == "status" {
+			continue
+		}
+
+		actual, found := live.Object[key]
+		resourceDrift.Fields = append(resourceDrift.Fields, driftedFields(key, expected, actual, found)...)
+	}
+
+	if len(resourceDrift.Fields) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(resourceDrift.Fields, func(i, j int) bool {
+		return resourceDrift.Fields[i].Path < resourceDrift.Fields[j].Path
+	})
+
+	return resourceDrift, nil
+}
+
+// foldSecretStringData returns the fields of the provided object. The write-only stringData of a secret is folded
+// into its data, encoded like the API server does, so that it is compared with the live data.
+func foldSecretStringData(object *unstructured.Unstructured) map[string]interface{} {
+	stringData, ok := object.Object["stringData"].(map[string]interface{})
+	if !ok || object.GroupVersionKind() != (schema.GroupVersionKind{Version: "v1", Kind: "Secret"}) {
+		return object.Object
+	}
+
+	data := map[string]interface{}{}
+	if encoded, ok := object.Object["data"].(map[string]interface{}); ok {
+		maps.Copy(data, encoded)
+	}
+	for key, value := range stringData {
+		data[key] = base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(value)))
+	}
+
+	fields := maps.Clone(object.Object)
+	delete(fields, "stringData")
+	fields["data"] = data
+
+	return fields
+}
+
+// driftedFields compares the expected value at the provided path with its live value.
+// Maps and lists of equal length are compared element by element, so only the fields set in the manifest are considered.
+func driftedFields(path string, expected, actual interface{}, found bool) []FieldDrift {
+	if !found {
+		if isZeroValue(expected) {
+			return nil
+		}
+
+		return []FieldDrift{{Path: path, Type: DiffRemoved, Expected: expected}}
+	}
+
+	switch expected := expected.(type) {
+	case map[string]interface{}:
+		actual, ok := actual.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		var fields []FieldDrift
+		for key, value := range expected {
+			actualValue, found := actual[key]
+			fields = append(fields, driftedFields(path+"."+key, value, actualValue, found)...)
+		}
+
+		return fields
+	case []interface{}:
+		actual, ok := actual.([]interface{})
+		if !ok || len(actual) != len(expected) {
+			break
+		}
+
+		var fields []FieldDrift
+		for i := range expected {
+			fields = append(fields, driftedFields(path+"["+strconv.Itoa(i)+"]", expected[i], actual[i], true)...)
+		}
+
+		return fields
+	default:
+		if equalFieldValues(path, expected, actual) {
+			return nil
+		}
+	}
+
+	return []FieldDrift{{Path: path, Type: DiffChanged, Expected: expected, Actual: actual}}
+}
+
+// equalFieldValues compares two scalar values at the provided path of a manifest and a live object.
+// Numbers are compared independently of their type. Resource quantities, which the API server normalizes,
+// are compared independently of their notation, e.g. '0.5' and '500m'.
+func equalFieldValues(path string, expected, actual interface{}) bool {
+	if expectedNumber, ok := toFloat(expected); ok {
+		actualNumber, ok := toFloat(actual)
+		return ok && expectedNumber == actualNumber
+	}
+
+	if expectedString, ok := expected.(string); ok {
+		actualString, ok := actual.(string)
+		if !ok {
+			return false
+		}
+		if expectedString == actualString {
+			return true
+		}
+
+		if !strings.Contains(path, ".resources.") {
+			return false
+		}
+
+		expectedQuantity, err := apiresource.ParseQuantity(expectedString)
+		if err != nil {
+			return false
+		}
+
+		actualQuantity, err := apiresource.ParseQuantity(actualString)
+		return err == nil && expectedQuantity.Cmp(actualQuantity) == 0
+	}
+
+	return reflect.DeepEqual(expected, actual)
+}
+
+// toFloat converts the numeric types produced by decoding JSON or YAML to float64.
+func toFloat(value interface{}) (float64, bool) {
+	switch value := value.(type) {
+	case int:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case float64:
+		return value, true
+	}
+
+	return 0, false
+}
+
+// isZeroValue returns true if the provided value is nil, empty or the zero value of its type.
+func isZeroValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
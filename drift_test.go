@@ -0,0 +1,193 @@
+package helmclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// testRESTClientGetter connects to the provided API server and maps the provided kinds.
+type testRESTClientGetter struct {
+	config *rest.Config
+	mapper meta.RESTMapper
+}
+
+func (g *testRESTClientGetter) ToRESTConfig() (*rest.Config, error) {
+	return g.config, nil
+}
+
+func (g *testRESTClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	return nil, nil
+}
+
+func (g *testRESTClientGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	return g.mapper, nil
+}
+
+func (g *testRESTClientGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
+	return nil
+}
+
+func TestDetectDrift(t *testing.T) {
+	c := newTestClient(t)
+
+	objects := map[string]string{
+		"/apis/apps/v1/namespaces/default/deployments/app": `{
+			"apiVersion": "apps/v1", "kind": "Deployment",
+			"metadata": {"name": "app", "namespace": "default", "labels": {"app": "app"}, "uid": "1234"},
+			"spec": {
+				"replicas": 3,
+				"template": {"spec": {"containers": [{
+					"name": "app", "image": "nginx:1.25", "imagePullPolicy": "IfNotPresent",
+					"resources": {"limits": {"cpu": "500m"}}
+				}]}}
+			},
+			"status": {"replicas": 3}
+		}`,
+		"/api/v1/namespaces/default/services/app": `{
+			"apiVersion": "v1", "kind": "Service",
+			"metadata": {"name": "app", "namespace": "default"},
+			"spec": {"clusterIP": "10.0.0.1", "ports": [{"port": 80, "protocol": "TCP"}]}
+		}`,
+		"/api/v1/namespaces/default/secrets/app": `{
+			"apiVersion": "v1", "kind": "Secret",
+			"metadata": {"name": "app", "namespace": "default"},
+			"data": {"user": "YWRtaW4=", "password": "c2VjcmV0", "token": "b2xk"}
+		}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		object, ok := objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"kind": "Status", "apiVersion": "v1", "status": "Failure", "reason": "NotFound", "code": 404}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(object))
+	}))
+	t.Cleanup(server.Close)
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Service"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, meta.RESTScopeNamespace)
+
+	c.ActionConfig.RESTClientGetter = &testRESTClientGetter{config: &rest.Config{Host: server.URL}, mapper: mapper}
+
+	err := c.ActionConfig.Releases.Create(&release.Release{
+		Name:      "app",
+		Namespace: "default",
+		Version:   2,
+		Info:      &release.Info{Status: release.StatusDeployed},
+		Manifest: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    app: app
+    team: web
+spec:
+  replicas: 2
+  template:
+    spec:
+      hostNetwork: false
+      containers:
+        - name: app
+          image: nginx:1.25
+          resources:
+            limits:
+              cpu: "0.5"
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+spec:
+  ports:
+    - port: 80
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app
+data:
+  user: YWRtaW4=
+stringData:
+  password: secret
+  token: new
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	drift, err := c.DetectDrift(context.Background(), "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !drift.Drifted() || drift.Revision != 2 {
+		t.Fatalf("expected revision 2 to have drifted, got %+v", drift)
+	}
+
+	if len(drift.Resources) != 4 {
+		t.Fatalf("expected 4 drifted resources, got %v", drift.Resources)
+	}
+
+	widget, configMap, deployment, secret := drift.Resources[0], drift.Resources[1], drift.Resources[2], drift.Resources[3]
+
+	if configMap.Kind != "ConfigMap" || configMap.Namespace != "default" || !configMap.Deleted {
+		t.Errorf("expected the ConfigMap to be deleted, got %+v", configMap)
+	}
+
+	if widget.Kind != "Widget" || !widget.Deleted {
+		t.Errorf("expected the Widget of an unknown type to be deleted, got %+v", widget)
+	}
+
+	expected := []FieldDrift{
+		{Path: "metadata.labels.team", Type: DiffRemoved, Expected: "web"},
+		{Path: "spec.replicas", Type: DiffChanged, Expected: float64(2), Actual: int64(3)},
+	}
+
+	if deployment.Kind != "Deployment" || deployment.Deleted || len(deployment.Fields) != len(expected) {
+		t.Fatalf("expected the fields %v of the Deployment to have drifted, got %+v", expected, deployment)
+	}
+
+	for i, field := range deployment.Fields {
+		if field != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], field)
+		}
+	}
+
+	// The stringData of the secret is compared with the encoded live data.
+	expectedSecret := FieldDrift{Path: "data.token", Type: DiffChanged, Expected: "bmV3", Actual: "b2xk"}
+	if secret.Kind != "Secret" || len(secret.Fields) != 1 || secret.Fields[0] != expectedSecret {
+		t.Errorf("expected the field %+v of the Secret to have drifted, got %+v", expectedSecret, secret)
+	}
+
+	if _, err := c.DetectDrift(context.Background(), "missing"); err == nil {
+		t.Error("expected an error for a missing release")
+	}
+}
@@ -0,0 +1,208 @@
+package helmclient
+
+import (
+	"errors"
+	"fmt"
+
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var (
+	// ErrKubeConfigMissing is returned if a client is constructed from a kubeconfig without providing one.
+	ErrKubeConfigMissing = errors.New("kubeconfig missing")
+	// ErrNamespaceNotSet is returned if an operation requires a namespace, but the client has none configured.
+	ErrNamespaceNotSet = errors.New("namespace not set")
+	// ErrReleaseNotFound is matched by errors caused by a missing release, see ReleaseNotFoundError.
+	ErrReleaseNotFound = errors.New("release not found")
+	// ErrChartRepoNotFound is returned if an operation refers to a chart repository that is not configured.
+	ErrChartRepoNotFound = errors.New("chart repository not found")
+	// ErrRegistryNotLoggedIn is returned if logging out of a registry the client is not logged in to.
+	ErrRegistryNotLoggedIn = errors.New("not logged in to registry")
+	// ErrChartVerificationFailed is returned if a chart could not be verified against its provenance file.
+	ErrChartVerificationFailed = errors.New("chart verification failed")
+	// ErrDuplicateRelease is returned if a release is declared more than once in a set of releases.
+	ErrDuplicateRelease = errors.New("duplicate release")
+	// ErrReleaseDependencyNotFound is returned if a release depends on a release that is not part of the applied set.
+	ErrReleaseDependencyNotFound = errors.New("release dependency not found")
+	// ErrReleaseDependencyCycle is returned if the dependencies between releases form a cycle.
+	ErrReleaseDependencyCycle = errors.New("release dependency cycle")
+	// ErrReleaseDependencyFailed is set on releases that were skipped, because one of their dependencies was not deployed.
+	ErrReleaseDependencyFailed = errors.New("release dependency failed")
+	// ErrApplyStopped is set on releases that were skipped, because applying was stopped after a failure.
+	ErrApplyStopped = errors.New("applying releases stopped")
+	// ErrReleaseSetNameNotSet is returned if a release set has no name to label its releases with.
+	ErrReleaseSetNameNotSet = errors.New("release set name not set")
+	// ErrNoSuccessfulRevision is returned if a release has no revision that was deployed successfully.
+	ErrNoSuccessfulRevision = errors.New("no successfully deployed revision")
+	// ErrInvalidChartSpec is matched by errors caused by an invalid ChartSpec, see InvalidChartSpecError.
+	ErrInvalidChartSpec = errors.New("invalid chart spec")
+	// ErrLintFailed is matched by errors caused by linting, see LintError.
+	ErrLintFailed = errors.New("lint failed")
+	// ErrUnsupportedChartType is matched by errors caused by charts that are not installable, see UnsupportedChartTypeError.
+	ErrUnsupportedChartType = errors.New("unsupported chart type")
+	// ErrUpgradeFailed is matched by errors caused by a failed upgrade, see UpgradeError.
+	ErrUpgradeFailed = errors.New("upgrade failed")
+	// ErrCRDStorageVersionChanged indicates that a CRD upgrade would change the storage version, see CRDUpgradeError.
+	ErrCRDStorageVersionChanged = errors.New("storage version changed")
+	// ErrCRDMultipleStorageVersions indicates that a CRD sets more than one storage version, see CRDUpgradeError.
+	ErrCRDMultipleStorageVersions = errors.New("more than one storage version set")
+	// ErrCRDUnsupportedAPIVersion indicates that a CRD uses an unknown API version, see CRDUpgradeError.
+	ErrCRDUnsupportedAPIVersion = errors.New("unsupported api-version")
+)
+
+// ReleaseNotFoundError is returned if an operation refers to a release that does not exist.
+type ReleaseNotFoundError struct {
+	// Name is the name of the missing release.
+	Name string
+	// Err is the error returned by helm.
+	Err error
+}
+
+func (e *ReleaseNotFoundError) Error() string {
+	return fmt.Sprintf("release %q not found: %v", e.Name, e.Err)
+}
+
+func (e *ReleaseNotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrReleaseNotFound.
+func (e *ReleaseNotFoundError) Is(target error) bool {
+	return target == ErrReleaseNotFound
+}
+
+// InvalidChartSpecError is returned if a ChartSpec is invalid, see ChartSpec.Validate.
+type InvalidChartSpecError struct {
+	// ReleaseName is the name of the release of the spec.
+	ReleaseName string
+	// Errors are the validation errors of the spec's fields.
+	Errors field.ErrorList
+}
+
+func (e *InvalidChartSpecError) Error() string {
+	return fmt.Sprintf("invalid chart spec for release %q: %v", e.ReleaseName, e.Errors.ToAggregate())
+}
+
+// Is reports whether the target is ErrInvalidChartSpec.
+func (e *InvalidChartSpecError) Is(target error) bool {
+	return target == ErrInvalidChartSpec
+}
+
+// LintError is returned if linting a chart reported errors.
+type LintError struct {
+	// ChartPath is the path of the linted chart.
+	ChartPath string
+	// Errors are the errors that caused linting to fail.
+	Errors []error
+	// Messages are all messages reported by the linter.
+	Messages []LintMessage
+}
+
+func (e *LintError) Error() string {
+	return fmt.Sprintf("linting for chartpath %q failed", e.ChartPath)
+}
+
+// Is reports whether the target is ErrLintFailed.
+func (e *LintError) Is(target error) bool {
+	return target == ErrLintFailed
+}
+
+func (e *LintError) Unwrap() []error {
+	return e.Errors
+}
+
+// UnsupportedChartTypeError is returned if a chart cannot be installed because of its type, e.g. a library chart.
+type UnsupportedChartTypeError struct {
+	// Chart is the name of the chart.
+	Chart string
+	// Type is the type of the chart.
+	Type string
+}
+
+func (e *UnsupportedChartTypeError) Error() string {
+	return fmt.Sprintf("chart %q has an unsupported type and is not installable: %q", e.Chart, e.Type)
+}
+
+// Is reports whether the target is ErrUnsupportedChartType.
+func (e *UnsupportedChartTypeError) Is(target error) bool {
+	return target == ErrUnsupportedChartType
+}
+
+// CRDUpgradeError is returned if a CRD of a chart cannot be upgraded.
+type CRDUpgradeError struct {
+	// CRD is the name of the CustomResourceDefinition.
+	CRD string
+	// Err is the cause of the failure, e.g. ErrCRDStorageVersionChanged.
+	Err error
+}
+
+func (e *CRDUpgradeError) Error() string {
+	return fmt.Sprintf("failed to upgrade CRD %q: %v", e.CRD, e.Err)
+}
+
+func (e *CRDUpgradeError) Unwrap() error {
+	return e.Err
+}
+
+// RollbackOutcome describes whether a rollback was performed after a failed upgrade.
+type RollbackOutcome string
+
+const (
+	// RollbackNotAttempted indicates that no rollback was performed.
+	RollbackNotAttempted RollbackOutcome = "not-attempted"
+	// RollbackSucceeded indicates that the release was rolled back successfully.
+	RollbackSucceeded RollbackOutcome = "succeeded"
+	// RollbackFailed indicates that rolling back the release failed as well.
+	RollbackFailed RollbackOutcome = "failed"
+)
+
+// UpgradeError is returned if upgrading a release failed.
+type UpgradeError struct {
+	// ReleaseName is the name of the upgraded release.
+	ReleaseName string
+	// Err is the error of the upgrade.
+	Err error
+	// Rollback describes whether the release was rolled back afterward.
+	Rollback RollbackOutcome
+	// RollbackErr is the error of the rollback if Rollback is RollbackFailed.
+	RollbackErr error
+}
+
+func (e *UpgradeError) Error() string {
+	switch e.Rollback {
+	case RollbackSucceeded:
+		return fmt.Sprintf("release failed, rollback succeeded: release error: %v", e.Err)
+	case RollbackFailed:
+		return fmt.Sprintf("release failed, rollback failed: release error: %v, rollback error: %v", e.Err, e.RollbackErr)
+	default:
+		return e.Err.Error()
+	}
+}
+
+func (e *UpgradeError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrUpgradeFailed.
+func (e *UpgradeError) Is(target error) bool {
+	return target == ErrUpgradeFailed
+}
+
+// validateSpec returns an InvalidChartSpecError if the provided spec is invalid.
+func validateSpec(spec *ChartSpec) error {
+	if errs := spec.Validate(); len(errs) > 0 {
+		return &InvalidChartSpecError{ReleaseName: spec.ReleaseName, Errors: errs}
+	}
+
+	return nil
+}
+
+// releaseError wraps the provided error into a ReleaseNotFoundError if it was caused by a missing release.
+func releaseError(name string, err error) error {
+	if err != nil && errors.Is(err, driver.ErrReleaseNotFound) {
+		return &ReleaseNotFoundError{Name: name, Err: err}
+	}
+
+	return err
+}
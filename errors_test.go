@@ -0,0 +1,75 @@
+package helmclient
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+func TestReleaseError(t *testing.T) {
+	err := releaseError("foo", fmt.Errorf("uninstall: Release not loaded: foo: %w", driver.ErrReleaseNotFound))
+
+	if !errors.Is(err, ErrReleaseNotFound) {
+		t.Errorf("expected error to match ErrReleaseNotFound, got %v", err)
+	}
+
+	if !errors.Is(err, driver.ErrReleaseNotFound) {
+		t.Errorf("expected error to still match driver.ErrReleaseNotFound, got %v", err)
+	}
+
+	var notFoundErr *ReleaseNotFoundError
+	if !errors.As(err, &notFoundErr) || notFoundErr.Name != "foo" {
+		t.Errorf("expected a ReleaseNotFoundError for release foo, got %v", err)
+	}
+
+	otherErr := errors.New("connection refused")
+	if err := releaseError("foo", otherErr); err != otherErr {
+		t.Errorf("expected unrelated errors to be returned unchanged, got %v", err)
+	}
+}
+
+func TestUpgradeError(t *testing.T) {
+	cause := errors.New("timed out waiting for the condition")
+
+	err := fmt.Errorf("reconcile: %w", &UpgradeError{
+		ReleaseName: "foo",
+		Err:         cause,
+		Rollback:    RollbackFailed,
+		RollbackErr: errors.New("rollback timed out"),
+	})
+
+	if !errors.Is(err, ErrUpgradeFailed) {
+		t.Errorf("expected error to match ErrUpgradeFailed, got %v", err)
+	}
+
+	if !errors.Is(err, cause) {
+		t.Errorf("expected error to match its cause, got %v", err)
+	}
+
+	var upgradeErr *UpgradeError
+	if !errors.As(err, &upgradeErr) || upgradeErr.Rollback != RollbackFailed {
+		t.Errorf("expected an UpgradeError with a failed rollback, got %v", err)
+	}
+
+	notAttempted := &UpgradeError{ReleaseName: "foo", Err: cause, Rollback: RollbackNotAttempted}
+	if notAttempted.Error() != cause.Error() {
+		t.Errorf("expected the message of the cause, got %q", notAttempted.Error())
+	}
+}
+
+func TestCRDUpgradeError(t *testing.T) {
+	err := &CRDUpgradeError{
+		CRD: "foos.example.com",
+		Err: fmt.Errorf("%w from %q to %q", ErrCRDStorageVersionChanged, "v1alpha1", "v1"),
+	}
+
+	if !errors.Is(err, ErrCRDStorageVersionChanged) {
+		t.Errorf("expected error to match ErrCRDStorageVersionChanged, got %v", err)
+	}
+
+	if errors.Is(err, ErrCRDMultipleStorageVersions) {
+		t.Errorf("expected error not to match ErrCRDMultipleStorageVersions, got %v", err)
+	}
+}
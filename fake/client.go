@@ -0,0 +1,184 @@
+// Package fakehelmclient provides a helmclient.Client for tests that need no Kubernetes API server.
+// Unlike the mocks of package mockhelmclient, the fake client behaves like a real one:
+// charts are rendered, releases and their revisions are recorded in memory and their resources are applied
+// to an in-memory cluster, see KubeClient.
+package fakehelmclient
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+
+	helmclient "github.com/mittwald/go-helm-client"
+)
+
+// fakeHost is the address of the API server of the fake client's REST config.
+// It is never contacted by the operations supported by the fake client.
+const fakeHost = "https://fake-cluster.invalid"
+
+// Options defines the options of a fake client.
+// Unlike helmclient.New, a nil helmclient.Options disables linting, debug logs and output.
+type Options struct {
+	*helmclient.Options
+	// Releases are copied to the storage before the client is returned. The resources of deployed releases are created in the cluster.
+	// Releases without a namespace are stored in the namespace of the client.
+	Releases []*release.Release
+	// Capabilities are the capabilities of the fake cluster. They default to chartutil.DefaultCapabilities.
+	Capabilities *chartutil.Capabilities
+}
+
+// Client is a helmclient.Client storing releases in memory and applying them to an in-memory cluster.
+// Operations that require an API server, e.g. upgrading CRDs or detecting drift, are not supported.
+type Client struct {
+	*helmclient.HelmClient
+	// KubeClient is the in-memory cluster the releases are applied to.
+	KubeClient *KubeClient
+	// capabilities are the capabilities of the fake cluster.
+	capabilities *chartutil.Capabilities
+}
+
+var _ helmclient.Client = &Client{}
+
+// NewClient returns a fake client using the provided options.
+func NewClient(options *Options) (*Client, error) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	clientOptions := &helmclient.Options{}
+	if options.Options != nil {
+		*clientOptions = *options.Options
+	}
+
+	if clientOptions.Namespace == "" {
+		clientOptions.Namespace = "default"
+	}
+
+	if clientOptions.DebugLog == nil {
+		clientOptions.DebugLog = func(string, ...interface{}) {}
+	}
+
+	if clientOptions.Output == nil {
+		clientOptions.Output = io.Discard
+	}
+
+	clientOptions.StorageDriver = helmclient.StorageDriverMemory
+	clientOptions.Driver = nil
+
+	helmClient, err := helmclient.NewClientFromRestConf(&helmclient.RestConfClientOptions{
+		Options:    clientOptions,
+		RestConfig: &rest.Config{Host: fakeHost},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	capabilities := options.Capabilities
+	if capabilities == nil {
+		capabilities = chartutil.DefaultCapabilities
+	}
+
+	c := newClient(helmClient.(*helmclient.HelmClient), NewKubeClient(clientOptions.Namespace), capabilities)
+
+	for _, rel := range options.Releases {
+		if err := c.seed(rel); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// newClient returns a fake client applying releases of the provided client to the provided cluster.
+func newClient(helmClient *helmclient.HelmClient, kubeClient *KubeClient, capabilities *chartutil.Capabilities) *Client {
+	helmClient.ActionConfig.KubeClient = kubeClient
+	helmClient.ActionConfig.Capabilities = capabilities
+
+	return &Client{HelmClient: helmClient, KubeClient: kubeClient, capabilities: capabilities}
+}
+
+// WithNamespace returns a fake client operating on the provided namespace of the same cluster and release storage.
+func (c *Client) WithNamespace(namespace string) (helmclient.Client, error) {
+	derived, err := c.HelmClient.WithNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClient(derived.(*helmclient.HelmClient), c.KubeClient.withNamespace(namespace), c.capabilities), nil
+}
+
+// seed stores a copy of the provided release and creates its resources if it is deployed.
+// The release is copied, so that later operations do not modify the caller's release.
+func (c *Client) seed(seeded *release.Release) error {
+	rel := copyRelease(seeded)
+	if rel.Namespace == "" {
+		rel.Namespace = c.Settings.Namespace()
+	}
+
+	if err := c.ActionConfig.Releases.Create(rel); err != nil {
+		return fmt.Errorf("failed to store release %q: %w", rel.Name, err)
+	}
+
+	if rel.Info == nil || rel.Info.Status != release.StatusDeployed {
+		return nil
+	}
+
+	resources, err := c.KubeClient.withNamespace(rel.Namespace).Build(strings.NewReader(rel.Manifest), false)
+	if err != nil {
+		return fmt.Errorf("failed to build the resources of release %q: %w", rel.Name, err)
+	}
+
+	for _, info := range resources {
+		// Mark the resources as owned by the release like helm does when applying them.
+		object := info.Object.(*unstructured.Unstructured)
+
+		labels := object.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels["app.kubernetes.io/managed-by"] = "Helm"
+		object.SetLabels(labels)
+
+		annotations := object.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations["meta.helm.sh/release-name"] = rel.Name
+		annotations["meta.helm.sh/release-namespace"] = rel.Namespace
+		object.SetAnnotations(annotations)
+	}
+
+	if _, err := c.KubeClient.Create(resources); err != nil {
+		return fmt.Errorf("failed to create the resources of release %q: %w", rel.Name, err)
+	}
+
+	return nil
+}
+
+// copyRelease returns a copy of the provided release that does not share the release info, hooks or labels.
+func copyRelease(rel *release.Release) *release.Release {
+	copied := *rel
+
+	if rel.Info != nil {
+		info := *rel.Info
+		copied.Info = &info
+	}
+
+	if rel.Hooks != nil {
+		copied.Hooks = make([]*release.Hook, len(rel.Hooks))
+		for i, hook := range rel.Hooks {
+			h := *hook
+			copied.Hooks[i] = &h
+		}
+	}
+
+	copied.Labels = maps.Clone(rel.Labels)
+
+	return &copied
+}
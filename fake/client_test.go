@@ -0,0 +1,294 @@
+package fakehelmclient
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	helmclient "github.com/mittwald/go-helm-client"
+)
+
+// writeChart writes a chart rendering a config map with the value 'greeting' and, if enabled, a secret.
+func writeChart(t *testing.T) string {
+	t.Helper()
+
+	files := map[string]string{
+		"Chart.yaml":               "apiVersion: v2\nname: app\nversion: 0.1.0\n",
+		"values.yaml":              "greeting: hello\nsecret: true\n",
+		"templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\ndata:\n  greeting: {{ .Values.greeting }}\n",
+		"templates/secret.yaml":    "{{ if .Values.secret }}apiVersion: v1\nkind: Secret\nmetadata:\n  name: {{ .Release.Name }}\n{{ end }}",
+	}
+
+	chartPath := filepath.Join(t.TempDir(), "app")
+	for name, content := range files {
+		path := filepath.Join(chartPath, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return chartPath
+}
+
+func newTestClient(t *testing.T, releases ...*release.Release) *Client {
+	t.Helper()
+
+	c, err := NewClient(&Options{
+		Options: &helmclient.Options{
+			RepositoryConfig: filepath.Join(t.TempDir(), "repositories.yaml"),
+			RepositoryCache:  t.TempDir(),
+			RegistryConfig:   filepath.Join(t.TempDir(), "config.json"),
+		},
+		Releases: releases,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestClientReleaseLifecycle(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	spec := &helmclient.ChartSpec{ReleaseName: "app", ChartName: writeChart(t), Namespace: "default"}
+
+	rel, err := c.InstallOrUpgradeChart(ctx, spec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rel.Version != 1 || rel.Info.Status != release.StatusDeployed {
+		t.Fatalf("expected revision 1 to be deployed, got revision %d %s", rel.Version, rel.Info.Status)
+	}
+
+	configMap, ok := c.KubeClient.Object("v1", "ConfigMap", "default", "app")
+	if !ok {
+		t.Fatalf("expected the config map to be created, got %v", c.KubeClient.Objects())
+	}
+
+	if greeting, _, _ := unstructured.NestedString(configMap.Object, "data", "greeting"); greeting != "hello" {
+		t.Errorf("expected the greeting 'hello', got %q", greeting)
+	}
+
+	if configMap.GetAnnotations()["meta.helm.sh/release-name"] != "app" {
+		t.Errorf("expected the config map to be owned by the release, got %v", configMap.GetAnnotations())
+	}
+
+	spec.ValuesYaml = "greeting: hi\nsecret: false\n"
+
+	rel, err = c.InstallOrUpgradeChart(ctx, spec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rel.Version != 2 {
+		t.Fatalf("expected revision 2, got %d", rel.Version)
+	}
+
+	configMap, _ = c.KubeClient.Object("v1", "ConfigMap", "default", "app")
+	if greeting, _, _ := unstructured.NestedString(configMap.Object, "data", "greeting"); greeting != "hi" {
+		t.Errorf("expected the greeting 'hi', got %q", greeting)
+	}
+
+	if _, ok := c.KubeClient.Object("v1", "Secret", "default", "app"); ok {
+		t.Error("expected the secret removed from the chart to be deleted")
+	}
+
+	rel, err = c.RollbackToRevision(ctx, "app", 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rel.Version != 3 {
+		t.Fatalf("expected revision 3, got %d", rel.Version)
+	}
+
+	if _, ok := c.KubeClient.Object("v1", "Secret", "default", "app"); !ok {
+		t.Error("expected the secret to be restored by the rollback")
+	}
+
+	history, err := c.ListReleaseHistory("app", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(history) != 3 {
+		t.Errorf("expected 3 revisions, got %d", len(history))
+	}
+
+	if err := c.UninstallReleaseByName("app"); err != nil {
+		t.Fatal(err)
+	}
+
+	if objects := c.KubeClient.Objects(); len(objects) != 0 {
+		t.Errorf("expected all resources to be deleted, got %v", objects)
+	}
+}
+
+func TestClientResourceConflict(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t, &release.Release{
+		Name:     "seeded",
+		Version:  1,
+		Info:     &release.Info{Status: release.StatusDeployed},
+		Manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n",
+	})
+
+	rel, err := c.GetRelease("seeded")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rel.Namespace != "default" {
+		t.Errorf("expected the seeded release to be stored in the namespace 'default', got %q", rel.Namespace)
+	}
+
+	if _, ok := c.KubeClient.Object("v1", "ConfigMap", "default", "app"); !ok {
+		t.Fatal("expected the resources of the seeded release to be created")
+	}
+
+	spec := &helmclient.ChartSpec{ReleaseName: "app", ChartName: writeChart(t), Namespace: "default"}
+	if _, err := c.InstallChart(ctx, spec, nil); err == nil {
+		t.Fatal("expected the config map owned by another release to conflict")
+	}
+
+	other, err := c.WithNamespace("other")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spec.Namespace = "other"
+	if _, err := other.InstallChart(ctx, spec, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := c.KubeClient.Object("v1", "ConfigMap", "other", "app"); !ok {
+		t.Error("expected the release of the derived client to be applied to the same cluster")
+	}
+}
+
+func TestClientApplyReleasesAcrossNamespaces(t *testing.T) {
+	c := newTestClient(t)
+	chartPath := writeChart(t)
+
+	specs := []*helmclient.ChartSpec{
+		{ReleaseName: "app", ChartName: chartPath},
+		{ReleaseName: "app", ChartName: chartPath, Namespace: "other", DependsOn: []string{"default/app"}},
+	}
+
+	for revision := 1; revision <= 2; revision++ {
+		results, err := c.ApplyReleases(context.Background(), specs, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, result := range results {
+			if result.Release.Version != revision {
+				t.Errorf("expected revision %d of the release in the namespace %q, got %d", revision, result.Namespace, result.Release.Version)
+			}
+		}
+	}
+
+	for _, namespace := range []string{"default", "other"} {
+		if _, ok := c.KubeClient.Object("v1", "ConfigMap", namespace, "app"); !ok {
+			t.Errorf("expected the release to be applied to the namespace %q, got %v", namespace, c.KubeClient.Objects())
+		}
+	}
+
+	other, err := c.WithNamespace("other")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	releases, err := other.ListDeployedReleases()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(releases) != 1 || releases[0].Namespace != "other" {
+		t.Errorf("expected the release of the namespace 'other' to be listed, got %v", releases)
+	}
+}
+
+func TestClientSeedCopiesReleases(t *testing.T) {
+	seeded := &release.Release{
+		Name:     "app",
+		Version:  1,
+		Info:     &release.Info{Status: release.StatusDeployed},
+		Manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n",
+	}
+
+	c := newTestClient(t, seeded)
+
+	spec := &helmclient.ChartSpec{ReleaseName: "app", ChartName: writeChart(t), Namespace: "default"}
+	if _, err := c.InstallOrUpgradeChart(context.Background(), spec, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if seeded.Namespace != "" || seeded.Info.Status != release.StatusDeployed {
+		t.Errorf("expected the seeded release to be left unchanged, got namespace %q and status %q", seeded.Namespace, seeded.Info.Status)
+	}
+
+	stored, err := c.ActionConfig.Releases.Get("app", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stored.Info.Status != release.StatusSuperseded {
+		t.Errorf("expected the stored revision to be superseded, got %q", stored.Info.Status)
+	}
+}
+
+func TestClientReconcileAcrossNamespaces(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+	chartPath := writeChart(t)
+
+	set := &helmclient.ReleaseSet{
+		Name: "staging",
+		Releases: []helmclient.ChartSpec{
+			{ReleaseName: "app", ChartName: chartPath},
+			{ReleaseName: "cache", ChartName: chartPath, Namespace: "other"},
+			{ReleaseName: "worker", ChartName: chartPath, Namespace: "other"},
+		},
+	}
+
+	result, err := c.Reconcile(ctx, set, &helmclient.ReconcileOptions{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Pruned) != 0 {
+		t.Errorf("expected no release to be pruned, got %v", result.Pruned)
+	}
+
+	set.Releases = set.Releases[:2]
+
+	result, err = c.Reconcile(ctx, set, &helmclient.ReconcileOptions{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Pruned) != 1 || result.Pruned[0].Name != "worker" || result.Pruned[0].Namespace != "other" {
+		t.Fatalf("expected the release 'worker' of the namespace 'other' to be pruned, got %v", result.Pruned)
+	}
+
+	if _, ok := c.KubeClient.Object("v1", "ConfigMap", "other", "worker"); ok {
+		t.Error("expected the resources of the pruned release to be deleted")
+	}
+
+	for namespace, name := range map[string]string{"default": "app", "other": "cache"} {
+		if _, ok := c.KubeClient.Object("v1", "ConfigMap", namespace, name); !ok {
+			t.Errorf("expected the release %q to be kept in the namespace %q", name, namespace)
+		}
+	}
+}
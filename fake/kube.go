@@ -0,0 +1,364 @@
+package fakehelmclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+
+	"helm.sh/helm/v3/pkg/kube"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/kubernetes/scheme"
+	restfake "k8s.io/client-go/rest/fake"
+)
+
+// clusterScopedKinds are the built-in kinds that are not namespaced.
+// Resources of any other kind are assumed to be namespaced.
+var clusterScopedKinds = map[string]bool{
+	"APIService":                     true,
+	"ClusterRole":                    true,
+	"ClusterRoleBinding":             true,
+	"CustomResourceDefinition":       true,
+	"IngressClass":                   true,
+	"MutatingWebhookConfiguration":   true,
+	"Namespace":                      true,
+	"Node":                           true,
+	"PersistentVolume":               true,
+	"PriorityClass":                  true,
+	"RuntimeClass":                   true,
+	"StorageClass":                   true,
+	"ValidatingWebhookConfiguration": true,
+}
+
+// KubeClient is a kube.Interface applying resources to an in-memory cluster instead of a Kubernetes API server.
+// Resources are created, updated and deleted as helm requests it, so that conflicts with existing resources
+// are detected like in a real cluster. Waiting for resources and hooks returns immediately.
+type KubeClient struct {
+	kubefake.PrintingKubeClient
+	namespace string
+	cluster   *cluster
+}
+
+var _ kube.Interface = &KubeClient{}
+
+// cluster holds the resources of the fake cluster.
+type cluster struct {
+	mu      sync.Mutex
+	objects map[objectKey]*unstructured.Unstructured
+}
+
+// objectKey identifies a resource of the fake cluster.
+type objectKey struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+}
+
+// NewKubeClient returns a KubeClient with an empty cluster, using the provided namespace
+// for namespaced resources that do not specify one.
+func NewKubeClient(namespace string) *KubeClient {
+	return &KubeClient{
+		PrintingKubeClient: kubefake.PrintingKubeClient{Out: io.Discard},
+		namespace:          namespace,
+		cluster:            &cluster{objects: map[objectKey]*unstructured.Unstructured{}},
+	}
+}
+
+// withNamespace returns a KubeClient for the provided namespace sharing the cluster.
+func (c *KubeClient) withNamespace(namespace string) *KubeClient {
+	return &KubeClient{PrintingKubeClient: c.PrintingKubeClient, namespace: namespace, cluster: c.cluster}
+}
+
+// ForNamespace returns a KubeClient for the provided namespace sharing the cluster.
+// It is used by helmclient.HelmClient.WithNamespace, e.g. when applying releases of other namespaces via ApplyReleases.
+func (c *KubeClient) ForNamespace(namespace string) kube.Interface {
+	return c.withNamespace(namespace)
+}
+
+// Objects returns copies of all resources of the cluster, sorted by namespace, kind and name.
+func (c *KubeClient) Objects() []*unstructured.Unstructured {
+	c.cluster.mu.Lock()
+	defer c.cluster.mu.Unlock()
+
+	objects := make([]*unstructured.Unstructured, 0, len(c.cluster.objects))
+	for _, object := range c.cluster.objects {
+		objects = append(objects, object.DeepCopy())
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		a, b := objects[i], objects[j]
+		if a.GetNamespace() != b.GetNamespace() {
+			return a.GetNamespace() < b.GetNamespace()
+		}
+		if a.GetKind() != b.GetKind() {
+			return a.GetKind() < b.GetKind()
+		}
+		return a.GetName() < b.GetName()
+	})
+
+	return objects
+}
+
+// Object returns a copy of the resource with the provided API version, kind, namespace and name, if it exists.
+// The namespace of cluster-scoped resources is empty.
+func (c *KubeClient) Object(apiVersion, kind, namespace, name string) (*unstructured.Unstructured, bool) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, false
+	}
+
+	object, ok := c.cluster.get(objectKey{gvk: gv.WithKind(kind), namespace: namespace, name: name})
+
+	return object, ok
+}
+
+// Build decodes the provided manifests into resources of the cluster.
+func (c *KubeClient) Build(reader io.Reader, _ bool) (kube.ResourceList, error) {
+	var resources kube.ResourceList
+
+	decoder := utilyaml.NewYAMLOrJSONDecoder(reader, 4096)
+	for {
+		object := &unstructured.Unstructured{}
+		if err := decoder.Decode(&object.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return resources, nil
+			}
+			return nil, err
+		}
+
+		if len(object.Object) == 0 {
+			continue
+		}
+
+		gvk := object.GroupVersionKind()
+		if gvk.Kind == "" || object.GetName() == "" {
+			return nil, fmt.Errorf("resource %s/%s %q is missing a kind or name", gvk.GroupVersion(), gvk.Kind, object.GetName())
+		}
+
+		scope := meta.RESTScopeNamespace
+		if clusterScopedKinds[gvk.Kind] {
+			scope = meta.RESTScopeRoot
+			object.SetNamespace("")
+		} else if object.GetNamespace() == "" {
+			object.SetNamespace(c.namespace)
+		}
+
+		resourceName, _ := meta.UnsafeGuessKindToResource(gvk)
+		resources = append(resources, &resource.Info{
+			Client: c.cluster.restClient(keyOf(object), resourceName.GroupResource()),
+			Mapping: &meta.RESTMapping{
+				Resource:         resourceName,
+				GroupVersionKind: gvk,
+				Scope:            scope,
+			},
+			Namespace: object.GetNamespace(),
+			Name:      object.GetName(),
+			Object:    object,
+		})
+	}
+}
+
+// BuildTable decodes the provided manifests like Build.
+func (c *KubeClient) BuildTable(reader io.Reader, validate bool) (kube.ResourceList, error) {
+	return c.Build(reader, validate)
+}
+
+// Create creates the provided resources. It fails if one of them exists already.
+func (c *KubeClient) Create(resources kube.ResourceList) (*kube.Result, error) {
+	c.cluster.mu.Lock()
+	defer c.cluster.mu.Unlock()
+
+	for _, info := range resources {
+		key, err := infoKey(info)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := c.cluster.objects[key]; ok {
+			return nil, apierrors.NewAlreadyExists(info.Mapping.Resource.GroupResource(), info.Name)
+		}
+	}
+
+	for _, info := range resources {
+		if err := c.cluster.store(info); err != nil {
+			return nil, err
+		}
+	}
+
+	return &kube.Result{Created: resources}, nil
+}
+
+// Update creates or updates the target resources and deletes the original resources that are not part of the target anymore.
+func (c *KubeClient) Update(original, target kube.ResourceList, _ bool) (*kube.Result, error) {
+	c.cluster.mu.Lock()
+	defer c.cluster.mu.Unlock()
+
+	result := &kube.Result{}
+	targetKeys := map[objectKey]bool{}
+
+	for _, info := range target {
+		key, err := infoKey(info)
+		if err != nil {
+			return nil, err
+		}
+
+		targetKeys[key] = true
+		if _, ok := c.cluster.objects[key]; ok {
+			result.Updated = append(result.Updated, info)
+		} else {
+			result.Created = append(result.Created, info)
+		}
+
+		if err := c.cluster.store(info); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, info := range original {
+		key, err := infoKey(info)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := c.cluster.objects[key]; ok && !targetKeys[key] {
+			delete(c.cluster.objects, key)
+			result.Deleted = append(result.Deleted, info)
+		}
+	}
+
+	return result, nil
+}
+
+// UpdateThreeWayMerge updates the resources like Update.
+func (c *KubeClient) UpdateThreeWayMerge(original, target kube.ResourceList, force bool) (*kube.Result, error) {
+	return c.Update(original, target, force)
+}
+
+// Delete deletes the provided resources. Missing resources are ignored, like helm does.
+func (c *KubeClient) Delete(resources kube.ResourceList) (*kube.Result, []error) {
+	c.cluster.mu.Lock()
+	defer c.cluster.mu.Unlock()
+
+	result := &kube.Result{}
+	for _, info := range resources {
+		key, err := infoKey(info)
+		if err != nil {
+			return nil, []error{err}
+		}
+
+		if _, ok := c.cluster.objects[key]; ok {
+			delete(c.cluster.objects, key)
+			result.Deleted = append(result.Deleted, info)
+		}
+	}
+
+	return result, nil
+}
+
+// DeleteWithPropagationPolicy deletes the provided resources like Delete.
+func (c *KubeClient) DeleteWithPropagationPolicy(resources kube.ResourceList, _ metav1.DeletionPropagation) (*kube.Result, []error) {
+	return c.Delete(resources)
+}
+
+// Get returns the live state of the provided resources, keyed by version and kind like helm's client.
+func (c *KubeClient) Get(resources kube.ResourceList, _ bool) (map[string][]runtime.Object, error) {
+	objects := map[string][]runtime.Object{}
+
+	for _, info := range resources {
+		key, err := infoKey(info)
+		if err != nil {
+			return nil, err
+		}
+
+		object, ok := c.cluster.get(key)
+		if !ok {
+			continue
+		}
+
+		versionKind := key.gvk.Version + "/" + key.gvk.Kind
+		objects[versionKind] = append(objects[versionKind], object)
+	}
+
+	return objects, nil
+}
+
+// get returns a copy of the resource with the provided key.
+func (c *cluster) get(key objectKey) (*unstructured.Unstructured, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	object, ok := c.objects[key]
+	if !ok {
+		return nil, false
+	}
+
+	return object.DeepCopy(), true
+}
+
+// store stores a copy of the resource of the provided info. The caller must hold the lock.
+func (c *cluster) store(info *resource.Info) error {
+	object, ok := info.Object.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("resource %s/%s is of unsupported type %T", info.Namespace, info.Name, info.Object)
+	}
+
+	c.objects[keyOf(object)] = object.DeepCopy()
+
+	return nil
+}
+
+// restClient returns a REST client serving the resource with the provided key from the cluster,
+// which is used by helm to look up existing resources before installing a release.
+func (c *cluster) restClient(key objectKey, groupResource schema.GroupResource) *restfake.RESTClient {
+	return &restfake.RESTClient{
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		Client: restfake.CreateHTTPClient(func(*http.Request) (*http.Response, error) {
+			var status int
+			var body interface{}
+
+			if object, ok := c.get(key); ok {
+				status, body = http.StatusOK, object.Object
+			} else {
+				status, body = http.StatusNotFound, apierrors.NewNotFound(groupResource, key.name).Status()
+			}
+
+			data, err := json.Marshal(body)
+			if err != nil {
+				return nil, err
+			}
+
+			return &http.Response{
+				StatusCode: status,
+				Header:     http.Header{"Content-Type": {"application/json"}},
+				Body:       io.NopCloser(bytes.NewReader(data)),
+			}, nil
+		}),
+	}
+}
+
+// infoKey returns the key of the resource of the provided info.
+func infoKey(info *resource.Info) (objectKey, error) {
+	object, ok := info.Object.(*unstructured.Unstructured)
+	if !ok {
+		return objectKey{}, fmt.Errorf("resource %s/%s is of unsupported type %T", info.Namespace, info.Name, info.Object)
+	}
+
+	return keyOf(object), nil
+}
+
+// keyOf returns the key of the provided resource.
+func keyOf(object *unstructured.Unstructured) objectKey {
+	return objectKey{gvk: object.GroupVersionKind(), namespace: object.GetNamespace(), name: object.GetName()}
+}
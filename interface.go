@@ -8,6 +8,7 @@ import (
 
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/registry"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/repo"
 )
@@ -16,28 +17,80 @@ import (
 // NOTE: This is an interface to allow for mocking in tests.
 type Client interface {
 	AddOrUpdateChartRepo(entry repo.Entry) error
+	AddOrUpdateChartRepoWithContext(ctx context.Context, entry repo.Entry) error
+	AddOrUpdateChartRepoWithOptions(ctx context.Context, entry repo.Entry, opts *ChartRepoOptions) (ChartRepoChange, error)
 	UpdateChartRepos() error
+	UpdateChartReposWithContext(ctx context.Context) error
+	UpdateChartReposWithOptions(ctx context.Context, opts *UpdateChartReposOptions) ([]*ChartRepoUpdateResult, error)
+	ListChartRepos() []*repo.Entry
+	RemoveChartRepo(name string) error
+	SearchCharts(opts *SearchChartsOptions) ([]*ChartSearchResult, error)
+	RegistryLogin(ctx context.Context, host string, opts *RegistryLoginOptions) error
+	RegistryLogout(host string) error
+	PushChart(ctx context.Context, chartArchive string, remote string, opts *RegistryOptions) (*registry.PushResult, error)
+	PullChart(ctx context.Context, ref string, opts *PullChartOptions) (string, error)
+	PackageChart(ctx context.Context, chartPath string, opts *PackageChartOptions) (string, error)
+	VerifyChart(chartArchive string, keyring string) (*ChartVerification, error)
+	ReleaseStatus(ctx context.Context, name string) (*ReleaseStatus, error)
+	DetectDrift(ctx context.Context, name string) (*ReleaseDrift, error)
+	RecoverRelease(ctx context.Context, name string, opts *RecoverReleaseOptions) (*ReleaseRecovery, error)
 	InstallOrUpgradeChart(ctx context.Context, spec *ChartSpec, opts *GenericHelmOptions) (*release.Release, error)
+	ApplyReleases(ctx context.Context, specs []*ChartSpec, opts *ApplyReleasesOptions) ([]*ReleaseApplyResult, error)
+	Reconcile(ctx context.Context, set *ReleaseSet, opts *ReconcileOptions) (*ReconcileResult, error)
 	InstallChart(ctx context.Context, spec *ChartSpec, opts *GenericHelmOptions) (*release.Release, error)
 	UpgradeChart(ctx context.Context, spec *ChartSpec, opts *GenericHelmOptions) (*release.Release, error)
+	DiffChart(ctx context.Context, spec *ChartSpec) (*ReleaseDiff, error)
+	DiffChartWithOptions(ctx context.Context, spec *ChartSpec, opts *GenericHelmOptions) (*ReleaseDiff, error)
 	ListDeployedReleases() ([]*release.Release, error)
+	ListDeployedReleasesWithContext(ctx context.Context) ([]*release.Release, error)
 	ListReleasesByStateMask(action.ListStates) ([]*release.Release, error)
+	ListReleasesByStateMaskWithContext(ctx context.Context, states action.ListStates) ([]*release.Release, error)
 	GetRelease(name string) (*release.Release, error)
+	GetReleaseWithContext(ctx context.Context, name string) (*release.Release, error)
 	// RollBack is an interface to abstract a rollback action.
 	RollBack
+	// FailedReleaseRollBack is an interface to abstract the rollback of a failed upgrade.
+	FailedReleaseRollBack
+	RollbackReleaseWithContext(ctx context.Context, spec *ChartSpec) error
+	RollbackToRevision(ctx context.Context, name string, revision int, opts *RollbackOptions) (*release.Release, error)
+	LastSuccessfulRevision(ctx context.Context, name string) (int, error)
 	GetReleaseValues(name string, allValues bool) (map[string]interface{}, error)
+	GetReleaseValuesWithContext(ctx context.Context, name string, allValues bool) (map[string]interface{}, error)
 	GetSettings() *cli.EnvSettings
 	GetProviders() getter.Providers
+	WithNamespace(namespace string) (Client, error)
 	UninstallRelease(spec *ChartSpec) error
+	UninstallReleaseWithContext(ctx context.Context, spec *ChartSpec) error
 	UninstallReleaseByName(name string) error
+	UninstallReleaseByNameWithContext(ctx context.Context, name string) error
 	TemplateChart(spec *ChartSpec, options *HelmTemplateOptions) ([]byte, error)
+	TemplateChartWithContext(ctx context.Context, spec *ChartSpec, options *HelmTemplateOptions) ([]byte, error)
 	LintChart(spec *ChartSpec) error
+	LintChartWithContext(ctx context.Context, spec *ChartSpec) error
+	LintChartWithOptions(ctx context.Context, spec *ChartSpec, opts *LintOptions) (*LintReport, error)
 	SetDebugLog(debugLog action.DebugLog)
 	ListReleaseHistory(name string, max int) ([]*release.Release, error)
+	ListReleaseHistoryWithContext(ctx context.Context, name string, max int) ([]*release.Release, error)
 	GetChart(chartName string, chartPathOptions *action.ChartPathOptions) (*chart.Chart, string, error)
+	GetChartWithContext(ctx context.Context, chartName string, chartPathOptions *action.ChartPathOptions) (*chart.Chart, string, error)
 	RunChartTests(releaseName string) (bool, error)
+	RunChartTestsWithContext(ctx context.Context, releaseName string) (bool, error)
 }
 
 type RollBack interface {
 	RollbackRelease(spec *ChartSpec) error
 }
+
+// FailedReleaseRollBack is a variant of RollBack receiving the details of the failed upgrade.
+// The failed release is nil if the upgrade failed before a revision was created.
+type FailedReleaseRollBack interface {
+	RollbackFailedRelease(ctx context.Context, spec *ChartSpec, failed *release.Release, upgradeErr error) error
+}
+
+// FailedReleaseRollBackFunc is a function implementing FailedReleaseRollBack.
+type FailedReleaseRollBackFunc func(ctx context.Context, spec *ChartSpec, failed *release.Release, upgradeErr error) error
+
+// RollbackFailedRelease calls f(ctx, spec, failed, upgradeErr).
+func (f FailedReleaseRollBackFunc) RollbackFailedRelease(ctx context.Context, spec *ChartSpec, failed *release.Release, upgradeErr error) error {
+	return f(ctx, spec, failed, upgradeErr)
+}
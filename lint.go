@@ -0,0 +1,103 @@
+package helmclient
+
+import (
+	"context"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/lint/support"
+)
+
+// lintSeverities maps the severities of helm's linter to LintSeverity.
+var lintSeverities = map[int]LintSeverity{
+	support.UnknownSev: LintSeverityUnknown,
+	support.InfoSev:    LintSeverityInfo,
+	support.WarningSev: LintSeverityWarning,
+	support.ErrorSev:   LintSeverityError,
+}
+
+// LintChart fetches a chart using the provided ChartSpec 'spec' and lints it's values.
+func (c *HelmClient) LintChart(spec *ChartSpec) error {
+	return c.LintChartWithContext(context.Background(), spec)
+}
+
+// LintChartWithContext fetches a chart using the provided ChartSpec 'spec' and lints it's values.
+func (c *HelmClient) LintChartWithContext(ctx context.Context, spec *ChartSpec) error {
+	_, err := c.LintChartWithOptions(ctx, spec, nil)
+	return err
+}
+
+// LintChartWithOptions fetches a chart using the provided ChartSpec 'spec', lints it's values
+// and returns a report of all messages of the linter.
+// If linting fails, the report is returned along with a LintError.
+func (c *HelmClient) LintChartWithOptions(ctx context.Context, spec *ChartSpec, opts *LintOptions) (*LintReport, error) {
+	chartPathOptions := &action.ChartPathOptions{}
+	mergeChartPathOptions(spec, chartPathOptions)
+
+	_, chartPath, err := c.getChart(ctx, spec.ChartName, chartPathOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := spec.GetValuesMap(c.Providers)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.lint(spec, chartPath, values, opts)
+}
+
+// lint lints a chart's values.
+func (c *HelmClient) lint(spec *ChartSpec, chartPath string, values map[string]interface{}, opts *LintOptions) (_ *LintReport, err error) {
+	defer c.observeOperation(OperationEvent{Operation: OperationLint, ReleaseName: spec.ReleaseName, Namespace: spec.Namespace, Chart: spec.ChartName}, time.Now(), &err)
+
+	if opts == nil {
+		opts = &LintOptions{}
+	}
+
+	client := action.NewLint()
+	client.Strict = opts.Strict
+	client.WithSubcharts = opts.WithSubcharts
+	client.SkipSchemaValidation = opts.SkipSchemaValidation
+	client.KubeVersion = opts.KubeVersion
+	client.Namespace = opts.Namespace
+	if client.Namespace == "" {
+		client.Namespace = spec.Namespace
+	}
+
+	result := client.Run([]string{chartPath}, values)
+
+	report := &LintReport{
+		ChartPath: chartPath,
+		Failed:    len(result.Errors) > 0,
+	}
+
+	for _, msg := range result.Messages {
+		report.Messages = append(report.Messages, LintMessage{
+			Severity: lintSeverities[msg.Severity],
+			Path:     msg.Path,
+			Message:  msg.Err.Error(),
+		})
+	}
+
+	// Errors that prevented linting altogether are not part of the linter's messages.
+	if result.TotalChartsLinted == 0 {
+		for _, err := range result.Errors {
+			report.Messages = append(report.Messages, LintMessage{
+				Severity: LintSeverityError,
+				Path:     chartPath,
+				Message:  err.Error(),
+			})
+		}
+	}
+
+	for _, err := range result.Errors {
+		c.DebugLog("Error %s", err)
+	}
+
+	if report.Failed {
+		return report, &LintError{ChartPath: chartPath, Errors: result.Errors, Messages: report.Messages}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,86 @@
+package helmclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLintChartWithOptions(t *testing.T) {
+	c := newTestClient(t)
+
+	// An invalid resource name passes linting with a warning.
+	chartPath := writeTestChart(t, map[string]string{
+		"Chart.yaml": "apiVersion: v2\nname: test-chart\nversion: 0.1.0\n",
+		"templates/configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: Invalid_Name
+`,
+	})
+	spec := &ChartSpec{ReleaseName: "test", ChartName: chartPath, Namespace: "default"}
+
+	report, err := c.LintChartWithOptions(context.Background(), spec, nil)
+	if err != nil {
+		t.Fatalf("expected linting to pass, got %v", err)
+	}
+
+	if report.Failed || !hasLintMessage(report, LintSeverityWarning, "templates/configmap.yaml") {
+		t.Fatalf("expected a warning for the invalid name, got %+v", report)
+	}
+
+	// In strict mode, the warning fails linting.
+	report, err = c.LintChartWithOptions(context.Background(), spec, &LintOptions{Strict: true})
+	if !errors.Is(err, ErrLintFailed) {
+		t.Fatalf("expected ErrLintFailed in strict mode, got %v", err)
+	}
+
+	var lintErr *LintError
+	if !errors.As(err, &lintErr) || len(lintErr.Messages) != len(report.Messages) {
+		t.Errorf("expected the LintError to carry the report's messages, got %v", err)
+	}
+
+	if len(lintErr.Errors) == 0 || !errors.Is(err, lintErr.Errors[0]) {
+		t.Errorf("expected the LintError to wrap the lint errors, got %v", lintErr.Errors)
+	}
+
+	if !report.Failed {
+		t.Errorf("expected the report to be marked as failed")
+	}
+}
+
+func TestLintChartWithOptionsNamespace(t *testing.T) {
+	c := newTestClient(t)
+
+	chartPath := writeTestChart(t, map[string]string{
+		"Chart.yaml": "apiVersion: v2\nname: test-chart\nversion: 0.1.0\n",
+		"templates/configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Namespace }}-config
+`,
+	})
+	spec := &ChartSpec{ReleaseName: "test", ChartName: chartPath, Namespace: "default"}
+
+	report, err := c.LintChartWithOptions(context.Background(), spec, nil)
+	if err != nil || hasLintMessage(report, LintSeverityWarning, "templates/configmap.yaml") {
+		t.Errorf("expected the chart to be linted in the namespace of the spec, got %+v, %v", report, err)
+	}
+
+	report, err = c.LintChartWithOptions(context.Background(), spec, &LintOptions{Namespace: "Invalid_Namespace"})
+	if err != nil || !hasLintMessage(report, LintSeverityWarning, "templates/configmap.yaml") {
+		t.Errorf("expected a warning when linting in the overridden namespace, got %+v, %v", report, err)
+	}
+}
+
+// hasLintMessage returns true if the report contains a message of the provided severity for a path containing 'path'.
+func hasLintMessage(report *LintReport, severity LintSeverity, path string) bool {
+	for _, msg := range report.Messages {
+		if msg.Severity == severity && strings.Contains(msg.Path, path) {
+			return true
+		}
+	}
+
+	return false
+}
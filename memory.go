@@ -0,0 +1,122 @@
+package helmclient
+
+import (
+	"maps"
+	"sync"
+
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+// memoryReleases holds the in-memory release storage of a client and the clients derived from it via WithNamespace.
+type memoryReleases struct {
+	mu      sync.Mutex
+	drivers map[string]*memoryDriver
+}
+
+func newMemoryReleases() *memoryReleases {
+	return &memoryReleases{drivers: map[string]*memoryDriver{}}
+}
+
+// driver returns the driver storing the releases of the provided namespace.
+func (m *memoryReleases) driver(namespace string) *memoryDriver {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	d, ok := m.drivers[namespace]
+	if !ok {
+		d = newMemoryDriver(namespace)
+		m.drivers[namespace] = d
+	}
+
+	return d
+}
+
+// memoryDriver stores releases of a single namespace in memory.
+// Unlike driver.Memory, it stores and returns copies of the releases, like the drivers persisting them,
+// so that concurrent operations do not share the releases they modify.
+type memoryDriver struct {
+	memory *driver.Memory
+}
+
+func newMemoryDriver(namespace string) *memoryDriver {
+	memory := driver.NewMemory()
+	memory.SetNamespace(namespace)
+
+	return &memoryDriver{memory: memory}
+}
+
+func (d *memoryDriver) Name() string {
+	return d.memory.Name()
+}
+
+func (d *memoryDriver) Get(key string) (*release.Release, error) {
+	rel, err := d.memory.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return copyRelease(rel), nil
+}
+
+func (d *memoryDriver) List(filter func(*release.Release) bool) ([]*release.Release, error) {
+	releases, err := d.memory.List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return copyReleases(releases), nil
+}
+
+func (d *memoryDriver) Query(labels map[string]string) ([]*release.Release, error) {
+	releases, err := d.memory.Query(labels)
+	if err != nil {
+		return nil, err
+	}
+
+	return copyReleases(releases), nil
+}
+
+func (d *memoryDriver) Create(key string, rel *release.Release) error {
+	return d.memory.Create(key, copyRelease(rel))
+}
+
+func (d *memoryDriver) Update(key string, rel *release.Release) error {
+	return d.memory.Update(key, copyRelease(rel))
+}
+
+func (d *memoryDriver) Delete(key string) (*release.Release, error) {
+	return d.memory.Delete(key)
+}
+
+// copyReleases returns copies of the provided releases.
+func copyReleases(releases []*release.Release) []*release.Release {
+	copied := make([]*release.Release, len(releases))
+	for i, rel := range releases {
+		copied[i] = copyRelease(rel)
+	}
+
+	return copied
+}
+
+// copyRelease returns a copy of the provided release that does not share the release info, hooks or labels.
+func copyRelease(rel *release.Release) *release.Release {
+	copied := *rel
+
+	if rel.Info != nil {
+		info := *rel.Info
+		copied.Info = &info
+	}
+
+	if rel.Hooks != nil {
+		copied.Hooks = make([]*release.Hook, len(rel.Hooks))
+		for i, hook := range rel.Hooks {
+			h := *hook
+			copied.Hooks[i] = &h
+		}
+	}
+
+	copied.Labels = maps.Clone(rel.Labels)
+
+	return &copied
+}
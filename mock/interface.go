@@ -19,6 +19,7 @@ import (
 	chart "helm.sh/helm/v3/pkg/chart"
 	cli "helm.sh/helm/v3/pkg/cli"
 	getter "helm.sh/helm/v3/pkg/getter"
+	registry "helm.sh/helm/v3/pkg/registry"
 	release "helm.sh/helm/v3/pkg/release"
 	repo "helm.sh/helm/v3/pkg/repo"
 )
@@ -60,6 +61,95 @@ func (mr *MockClientMockRecorder) AddOrUpdateChartRepo(entry any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOrUpdateChartRepo", reflect.TypeOf((*MockClient)(nil).AddOrUpdateChartRepo), entry)
 }
 
+// AddOrUpdateChartRepoWithContext mocks base method.
+func (m *MockClient) AddOrUpdateChartRepoWithContext(ctx context.Context, entry repo.Entry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOrUpdateChartRepoWithContext", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddOrUpdateChartRepoWithContext indicates an expected call of AddOrUpdateChartRepoWithContext.
+func (mr *MockClientMockRecorder) AddOrUpdateChartRepoWithContext(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOrUpdateChartRepoWithContext", reflect.TypeOf((*MockClient)(nil).AddOrUpdateChartRepoWithContext), ctx, entry)
+}
+
+// AddOrUpdateChartRepoWithOptions mocks base method.
+func (m *MockClient) AddOrUpdateChartRepoWithOptions(ctx context.Context, entry repo.Entry, opts *helmclient.ChartRepoOptions) (helmclient.ChartRepoChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOrUpdateChartRepoWithOptions", ctx, entry, opts)
+	ret0, _ := ret[0].(helmclient.ChartRepoChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddOrUpdateChartRepoWithOptions indicates an expected call of AddOrUpdateChartRepoWithOptions.
+func (mr *MockClientMockRecorder) AddOrUpdateChartRepoWithOptions(ctx, entry, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOrUpdateChartRepoWithOptions", reflect.TypeOf((*MockClient)(nil).AddOrUpdateChartRepoWithOptions), ctx, entry, opts)
+}
+
+// ApplyReleases mocks base method.
+func (m *MockClient) ApplyReleases(ctx context.Context, specs []*helmclient.ChartSpec, opts *helmclient.ApplyReleasesOptions) ([]*helmclient.ReleaseApplyResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyReleases", ctx, specs, opts)
+	ret0, _ := ret[0].([]*helmclient.ReleaseApplyResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyReleases indicates an expected call of ApplyReleases.
+func (mr *MockClientMockRecorder) ApplyReleases(ctx, specs, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyReleases", reflect.TypeOf((*MockClient)(nil).ApplyReleases), ctx, specs, opts)
+}
+
+// DetectDrift mocks base method.
+func (m *MockClient) DetectDrift(ctx context.Context, name string) (*helmclient.ReleaseDrift, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectDrift", ctx, name)
+	ret0, _ := ret[0].(*helmclient.ReleaseDrift)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetectDrift indicates an expected call of DetectDrift.
+func (mr *MockClientMockRecorder) DetectDrift(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectDrift", reflect.TypeOf((*MockClient)(nil).DetectDrift), ctx, name)
+}
+
+// DiffChart mocks base method.
+func (m *MockClient) DiffChart(ctx context.Context, spec *helmclient.ChartSpec) (*helmclient.ReleaseDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffChart", ctx, spec)
+	ret0, _ := ret[0].(*helmclient.ReleaseDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffChart indicates an expected call of DiffChart.
+func (mr *MockClientMockRecorder) DiffChart(ctx, spec any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffChart", reflect.TypeOf((*MockClient)(nil).DiffChart), ctx, spec)
+}
+
+// DiffChartWithOptions mocks base method.
+func (m *MockClient) DiffChartWithOptions(ctx context.Context, spec *helmclient.ChartSpec, opts *helmclient.GenericHelmOptions) (*helmclient.ReleaseDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffChartWithOptions", ctx, spec, opts)
+	ret0, _ := ret[0].(*helmclient.ReleaseDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffChartWithOptions indicates an expected call of DiffChartWithOptions.
+func (mr *MockClientMockRecorder) DiffChartWithOptions(ctx, spec, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffChartWithOptions", reflect.TypeOf((*MockClient)(nil).DiffChartWithOptions), ctx, spec, opts)
+}
+
 // GetChart mocks base method.
 func (m *MockClient) GetChart(chartName string, chartPathOptions *action.ChartPathOptions) (*chart.Chart, string, error) {
 	m.ctrl.T.Helper()
@@ -76,6 +166,22 @@ func (mr *MockClientMockRecorder) GetChart(chartName, chartPathOptions any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChart", reflect.TypeOf((*MockClient)(nil).GetChart), chartName, chartPathOptions)
 }
 
+// GetChartWithContext mocks base method.
+func (m *MockClient) GetChartWithContext(ctx context.Context, chartName string, chartPathOptions *action.ChartPathOptions) (*chart.Chart, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChartWithContext", ctx, chartName, chartPathOptions)
+	ret0, _ := ret[0].(*chart.Chart)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetChartWithContext indicates an expected call of GetChartWithContext.
+func (mr *MockClientMockRecorder) GetChartWithContext(ctx, chartName, chartPathOptions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChartWithContext", reflect.TypeOf((*MockClient)(nil).GetChartWithContext), ctx, chartName, chartPathOptions)
+}
+
 // GetProviders mocks base method.
 func (m *MockClient) GetProviders() getter.Providers {
 	m.ctrl.T.Helper()
@@ -120,6 +226,36 @@ func (mr *MockClientMockRecorder) GetReleaseValues(name, allValues any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReleaseValues", reflect.TypeOf((*MockClient)(nil).GetReleaseValues), name, allValues)
 }
 
+// GetReleaseValuesWithContext mocks base method.
+func (m *MockClient) GetReleaseValuesWithContext(ctx context.Context, name string, allValues bool) (map[string]any, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReleaseValuesWithContext", ctx, name, allValues)
+	ret0, _ := ret[0].(map[string]any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReleaseValuesWithContext indicates an expected call of GetReleaseValuesWithContext.
+func (mr *MockClientMockRecorder) GetReleaseValuesWithContext(ctx, name, allValues any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReleaseValuesWithContext", reflect.TypeOf((*MockClient)(nil).GetReleaseValuesWithContext), ctx, name, allValues)
+}
+
+// GetReleaseWithContext mocks base method.
+func (m *MockClient) GetReleaseWithContext(ctx context.Context, name string) (*release.Release, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReleaseWithContext", ctx, name)
+	ret0, _ := ret[0].(*release.Release)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReleaseWithContext indicates an expected call of GetReleaseWithContext.
+func (mr *MockClientMockRecorder) GetReleaseWithContext(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReleaseWithContext", reflect.TypeOf((*MockClient)(nil).GetReleaseWithContext), ctx, name)
+}
+
 // GetSettings mocks base method.
 func (m *MockClient) GetSettings() *cli.EnvSettings {
 	m.ctrl.T.Helper()
@@ -164,6 +300,21 @@ func (mr *MockClientMockRecorder) InstallOrUpgradeChart(ctx, spec, opts any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallOrUpgradeChart", reflect.TypeOf((*MockClient)(nil).InstallOrUpgradeChart), ctx, spec, opts)
 }
 
+// LastSuccessfulRevision mocks base method.
+func (m *MockClient) LastSuccessfulRevision(ctx context.Context, name string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastSuccessfulRevision", ctx, name)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastSuccessfulRevision indicates an expected call of LastSuccessfulRevision.
+func (mr *MockClientMockRecorder) LastSuccessfulRevision(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastSuccessfulRevision", reflect.TypeOf((*MockClient)(nil).LastSuccessfulRevision), ctx, name)
+}
+
 // LintChart mocks base method.
 func (m *MockClient) LintChart(spec *helmclient.ChartSpec) error {
 	m.ctrl.T.Helper()
@@ -178,6 +329,49 @@ func (mr *MockClientMockRecorder) LintChart(spec any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LintChart", reflect.TypeOf((*MockClient)(nil).LintChart), spec)
 }
 
+// LintChartWithContext mocks base method.
+func (m *MockClient) LintChartWithContext(ctx context.Context, spec *helmclient.ChartSpec) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LintChartWithContext", ctx, spec)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LintChartWithContext indicates an expected call of LintChartWithContext.
+func (mr *MockClientMockRecorder) LintChartWithContext(ctx, spec any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LintChartWithContext", reflect.TypeOf((*MockClient)(nil).LintChartWithContext), ctx, spec)
+}
+
+// LintChartWithOptions mocks base method.
+func (m *MockClient) LintChartWithOptions(ctx context.Context, spec *helmclient.ChartSpec, opts *helmclient.LintOptions) (*helmclient.LintReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LintChartWithOptions", ctx, spec, opts)
+	ret0, _ := ret[0].(*helmclient.LintReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LintChartWithOptions indicates an expected call of LintChartWithOptions.
+func (mr *MockClientMockRecorder) LintChartWithOptions(ctx, spec, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LintChartWithOptions", reflect.TypeOf((*MockClient)(nil).LintChartWithOptions), ctx, spec, opts)
+}
+
+// ListChartRepos mocks base method.
+func (m *MockClient) ListChartRepos() []*repo.Entry {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChartRepos")
+	ret0, _ := ret[0].([]*repo.Entry)
+	return ret0
+}
+
+// ListChartRepos indicates an expected call of ListChartRepos.
+func (mr *MockClientMockRecorder) ListChartRepos() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChartRepos", reflect.TypeOf((*MockClient)(nil).ListChartRepos))
+}
+
 // ListDeployedReleases mocks base method.
 func (m *MockClient) ListDeployedReleases() ([]*release.Release, error) {
 	m.ctrl.T.Helper()
@@ -193,6 +387,21 @@ func (mr *MockClientMockRecorder) ListDeployedReleases() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeployedReleases", reflect.TypeOf((*MockClient)(nil).ListDeployedReleases))
 }
 
+// ListDeployedReleasesWithContext mocks base method.
+func (m *MockClient) ListDeployedReleasesWithContext(ctx context.Context) ([]*release.Release, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeployedReleasesWithContext", ctx)
+	ret0, _ := ret[0].([]*release.Release)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeployedReleasesWithContext indicates an expected call of ListDeployedReleasesWithContext.
+func (mr *MockClientMockRecorder) ListDeployedReleasesWithContext(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeployedReleasesWithContext", reflect.TypeOf((*MockClient)(nil).ListDeployedReleasesWithContext), ctx)
+}
+
 // ListReleaseHistory mocks base method.
 func (m *MockClient) ListReleaseHistory(name string, max int) ([]*release.Release, error) {
 	m.ctrl.T.Helper()
@@ -208,6 +417,21 @@ func (mr *MockClientMockRecorder) ListReleaseHistory(name, max any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReleaseHistory", reflect.TypeOf((*MockClient)(nil).ListReleaseHistory), name, max)
 }
 
+// ListReleaseHistoryWithContext mocks base method.
+func (m *MockClient) ListReleaseHistoryWithContext(ctx context.Context, name string, max int) ([]*release.Release, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReleaseHistoryWithContext", ctx, name, max)
+	ret0, _ := ret[0].([]*release.Release)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReleaseHistoryWithContext indicates an expected call of ListReleaseHistoryWithContext.
+func (mr *MockClientMockRecorder) ListReleaseHistoryWithContext(ctx, name, max any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReleaseHistoryWithContext", reflect.TypeOf((*MockClient)(nil).ListReleaseHistoryWithContext), ctx, name, max)
+}
+
 // ListReleasesByStateMask mocks base method.
 func (m *MockClient) ListReleasesByStateMask(arg0 action.ListStates) ([]*release.Release, error) {
 	m.ctrl.T.Helper()
@@ -223,6 +447,167 @@ func (mr *MockClientMockRecorder) ListReleasesByStateMask(arg0 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReleasesByStateMask", reflect.TypeOf((*MockClient)(nil).ListReleasesByStateMask), arg0)
 }
 
+// ListReleasesByStateMaskWithContext mocks base method.
+func (m *MockClient) ListReleasesByStateMaskWithContext(ctx context.Context, states action.ListStates) ([]*release.Release, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReleasesByStateMaskWithContext", ctx, states)
+	ret0, _ := ret[0].([]*release.Release)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReleasesByStateMaskWithContext indicates an expected call of ListReleasesByStateMaskWithContext.
+func (mr *MockClientMockRecorder) ListReleasesByStateMaskWithContext(ctx, states any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReleasesByStateMaskWithContext", reflect.TypeOf((*MockClient)(nil).ListReleasesByStateMaskWithContext), ctx, states)
+}
+
+// PackageChart mocks base method.
+func (m *MockClient) PackageChart(ctx context.Context, chartPath string, opts *helmclient.PackageChartOptions) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PackageChart", ctx, chartPath, opts)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PackageChart indicates an expected call of PackageChart.
+func (mr *MockClientMockRecorder) PackageChart(ctx, chartPath, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PackageChart", reflect.TypeOf((*MockClient)(nil).PackageChart), ctx, chartPath, opts)
+}
+
+// PullChart mocks base method.
+func (m *MockClient) PullChart(ctx context.Context, ref string, opts *helmclient.PullChartOptions) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PullChart", ctx, ref, opts)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PullChart indicates an expected call of PullChart.
+func (mr *MockClientMockRecorder) PullChart(ctx, ref, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullChart", reflect.TypeOf((*MockClient)(nil).PullChart), ctx, ref, opts)
+}
+
+// PushChart mocks base method.
+func (m *MockClient) PushChart(ctx context.Context, chartArchive, remote string, opts *helmclient.RegistryOptions) (*registry.PushResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PushChart", ctx, chartArchive, remote, opts)
+	ret0, _ := ret[0].(*registry.PushResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PushChart indicates an expected call of PushChart.
+func (mr *MockClientMockRecorder) PushChart(ctx, chartArchive, remote, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushChart", reflect.TypeOf((*MockClient)(nil).PushChart), ctx, chartArchive, remote, opts)
+}
+
+// Reconcile mocks base method.
+func (m *MockClient) Reconcile(ctx context.Context, set *helmclient.ReleaseSet, opts *helmclient.ReconcileOptions) (*helmclient.ReconcileResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconcile", ctx, set, opts)
+	ret0, _ := ret[0].(*helmclient.ReconcileResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reconcile indicates an expected call of Reconcile.
+func (mr *MockClientMockRecorder) Reconcile(ctx, set, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockClient)(nil).Reconcile), ctx, set, opts)
+}
+
+// RecoverRelease mocks base method.
+func (m *MockClient) RecoverRelease(ctx context.Context, name string, opts *helmclient.RecoverReleaseOptions) (*helmclient.ReleaseRecovery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecoverRelease", ctx, name, opts)
+	ret0, _ := ret[0].(*helmclient.ReleaseRecovery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecoverRelease indicates an expected call of RecoverRelease.
+func (mr *MockClientMockRecorder) RecoverRelease(ctx, name, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecoverRelease", reflect.TypeOf((*MockClient)(nil).RecoverRelease), ctx, name, opts)
+}
+
+// RegistryLogin mocks base method.
+func (m *MockClient) RegistryLogin(ctx context.Context, host string, opts *helmclient.RegistryLoginOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegistryLogin", ctx, host, opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegistryLogin indicates an expected call of RegistryLogin.
+func (mr *MockClientMockRecorder) RegistryLogin(ctx, host, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegistryLogin", reflect.TypeOf((*MockClient)(nil).RegistryLogin), ctx, host, opts)
+}
+
+// RegistryLogout mocks base method.
+func (m *MockClient) RegistryLogout(host string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegistryLogout", host)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegistryLogout indicates an expected call of RegistryLogout.
+func (mr *MockClientMockRecorder) RegistryLogout(host any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegistryLogout", reflect.TypeOf((*MockClient)(nil).RegistryLogout), host)
+}
+
+// ReleaseStatus mocks base method.
+func (m *MockClient) ReleaseStatus(ctx context.Context, name string) (*helmclient.ReleaseStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseStatus", ctx, name)
+	ret0, _ := ret[0].(*helmclient.ReleaseStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseStatus indicates an expected call of ReleaseStatus.
+func (mr *MockClientMockRecorder) ReleaseStatus(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseStatus", reflect.TypeOf((*MockClient)(nil).ReleaseStatus), ctx, name)
+}
+
+// RemoveChartRepo mocks base method.
+func (m *MockClient) RemoveChartRepo(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveChartRepo", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveChartRepo indicates an expected call of RemoveChartRepo.
+func (mr *MockClientMockRecorder) RemoveChartRepo(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveChartRepo", reflect.TypeOf((*MockClient)(nil).RemoveChartRepo), name)
+}
+
+// RollbackFailedRelease mocks base method.
+func (m *MockClient) RollbackFailedRelease(ctx context.Context, spec *helmclient.ChartSpec, failed *release.Release, upgradeErr error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackFailedRelease", ctx, spec, failed, upgradeErr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollbackFailedRelease indicates an expected call of RollbackFailedRelease.
+func (mr *MockClientMockRecorder) RollbackFailedRelease(ctx, spec, failed, upgradeErr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackFailedRelease", reflect.TypeOf((*MockClient)(nil).RollbackFailedRelease), ctx, spec, failed, upgradeErr)
+}
+
 // RollbackRelease mocks base method.
 func (m *MockClient) RollbackRelease(spec *helmclient.ChartSpec) error {
 	m.ctrl.T.Helper()
@@ -237,6 +622,35 @@ func (mr *MockClientMockRecorder) RollbackRelease(spec any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackRelease", reflect.TypeOf((*MockClient)(nil).RollbackRelease), spec)
 }
 
+// RollbackReleaseWithContext mocks base method.
+func (m *MockClient) RollbackReleaseWithContext(ctx context.Context, spec *helmclient.ChartSpec) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackReleaseWithContext", ctx, spec)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollbackReleaseWithContext indicates an expected call of RollbackReleaseWithContext.
+func (mr *MockClientMockRecorder) RollbackReleaseWithContext(ctx, spec any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackReleaseWithContext", reflect.TypeOf((*MockClient)(nil).RollbackReleaseWithContext), ctx, spec)
+}
+
+// RollbackToRevision mocks base method.
+func (m *MockClient) RollbackToRevision(ctx context.Context, name string, revision int, opts *helmclient.RollbackOptions) (*release.Release, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackToRevision", ctx, name, revision, opts)
+	ret0, _ := ret[0].(*release.Release)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollbackToRevision indicates an expected call of RollbackToRevision.
+func (mr *MockClientMockRecorder) RollbackToRevision(ctx, name, revision, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackToRevision", reflect.TypeOf((*MockClient)(nil).RollbackToRevision), ctx, name, revision, opts)
+}
+
 // RunChartTests mocks base method.
 func (m *MockClient) RunChartTests(releaseName string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -252,6 +666,36 @@ func (mr *MockClientMockRecorder) RunChartTests(releaseName any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunChartTests", reflect.TypeOf((*MockClient)(nil).RunChartTests), releaseName)
 }
 
+// RunChartTestsWithContext mocks base method.
+func (m *MockClient) RunChartTestsWithContext(ctx context.Context, releaseName string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunChartTestsWithContext", ctx, releaseName)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunChartTestsWithContext indicates an expected call of RunChartTestsWithContext.
+func (mr *MockClientMockRecorder) RunChartTestsWithContext(ctx, releaseName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunChartTestsWithContext", reflect.TypeOf((*MockClient)(nil).RunChartTestsWithContext), ctx, releaseName)
+}
+
+// SearchCharts mocks base method.
+func (m *MockClient) SearchCharts(opts *helmclient.SearchChartsOptions) ([]*helmclient.ChartSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchCharts", opts)
+	ret0, _ := ret[0].([]*helmclient.ChartSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchCharts indicates an expected call of SearchCharts.
+func (mr *MockClientMockRecorder) SearchCharts(opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchCharts", reflect.TypeOf((*MockClient)(nil).SearchCharts), opts)
+}
+
 // SetDebugLog mocks base method.
 func (m *MockClient) SetDebugLog(debugLog action.DebugLog) {
 	m.ctrl.T.Helper()
@@ -279,6 +723,21 @@ func (mr *MockClientMockRecorder) TemplateChart(spec, options any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TemplateChart", reflect.TypeOf((*MockClient)(nil).TemplateChart), spec, options)
 }
 
+// TemplateChartWithContext mocks base method.
+func (m *MockClient) TemplateChartWithContext(ctx context.Context, spec *helmclient.ChartSpec, options *helmclient.HelmTemplateOptions) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TemplateChartWithContext", ctx, spec, options)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TemplateChartWithContext indicates an expected call of TemplateChartWithContext.
+func (mr *MockClientMockRecorder) TemplateChartWithContext(ctx, spec, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TemplateChartWithContext", reflect.TypeOf((*MockClient)(nil).TemplateChartWithContext), ctx, spec, options)
+}
+
 // UninstallRelease mocks base method.
 func (m *MockClient) UninstallRelease(spec *helmclient.ChartSpec) error {
 	m.ctrl.T.Helper()
@@ -307,6 +766,34 @@ func (mr *MockClientMockRecorder) UninstallReleaseByName(name any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UninstallReleaseByName", reflect.TypeOf((*MockClient)(nil).UninstallReleaseByName), name)
 }
 
+// UninstallReleaseByNameWithContext mocks base method.
+func (m *MockClient) UninstallReleaseByNameWithContext(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UninstallReleaseByNameWithContext", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UninstallReleaseByNameWithContext indicates an expected call of UninstallReleaseByNameWithContext.
+func (mr *MockClientMockRecorder) UninstallReleaseByNameWithContext(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UninstallReleaseByNameWithContext", reflect.TypeOf((*MockClient)(nil).UninstallReleaseByNameWithContext), ctx, name)
+}
+
+// UninstallReleaseWithContext mocks base method.
+func (m *MockClient) UninstallReleaseWithContext(ctx context.Context, spec *helmclient.ChartSpec) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UninstallReleaseWithContext", ctx, spec)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UninstallReleaseWithContext indicates an expected call of UninstallReleaseWithContext.
+func (mr *MockClientMockRecorder) UninstallReleaseWithContext(ctx, spec any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UninstallReleaseWithContext", reflect.TypeOf((*MockClient)(nil).UninstallReleaseWithContext), ctx, spec)
+}
+
 // UpdateChartRepos mocks base method.
 func (m *MockClient) UpdateChartRepos() error {
 	m.ctrl.T.Helper()
@@ -321,6 +808,35 @@ func (mr *MockClientMockRecorder) UpdateChartRepos() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChartRepos", reflect.TypeOf((*MockClient)(nil).UpdateChartRepos))
 }
 
+// UpdateChartReposWithContext mocks base method.
+func (m *MockClient) UpdateChartReposWithContext(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateChartReposWithContext", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateChartReposWithContext indicates an expected call of UpdateChartReposWithContext.
+func (mr *MockClientMockRecorder) UpdateChartReposWithContext(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChartReposWithContext", reflect.TypeOf((*MockClient)(nil).UpdateChartReposWithContext), ctx)
+}
+
+// UpdateChartReposWithOptions mocks base method.
+func (m *MockClient) UpdateChartReposWithOptions(ctx context.Context, opts *helmclient.UpdateChartReposOptions) ([]*helmclient.ChartRepoUpdateResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateChartReposWithOptions", ctx, opts)
+	ret0, _ := ret[0].([]*helmclient.ChartRepoUpdateResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateChartReposWithOptions indicates an expected call of UpdateChartReposWithOptions.
+func (mr *MockClientMockRecorder) UpdateChartReposWithOptions(ctx, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChartReposWithOptions", reflect.TypeOf((*MockClient)(nil).UpdateChartReposWithOptions), ctx, opts)
+}
+
 // UpgradeChart mocks base method.
 func (m *MockClient) UpgradeChart(ctx context.Context, spec *helmclient.ChartSpec, opts *helmclient.GenericHelmOptions) (*release.Release, error) {
 	m.ctrl.T.Helper()
@@ -336,6 +852,36 @@ func (mr *MockClientMockRecorder) UpgradeChart(ctx, spec, opts any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeChart", reflect.TypeOf((*MockClient)(nil).UpgradeChart), ctx, spec, opts)
 }
 
+// VerifyChart mocks base method.
+func (m *MockClient) VerifyChart(chartArchive, keyring string) (*helmclient.ChartVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyChart", chartArchive, keyring)
+	ret0, _ := ret[0].(*helmclient.ChartVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyChart indicates an expected call of VerifyChart.
+func (mr *MockClientMockRecorder) VerifyChart(chartArchive, keyring any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyChart", reflect.TypeOf((*MockClient)(nil).VerifyChart), chartArchive, keyring)
+}
+
+// WithNamespace mocks base method.
+func (m *MockClient) WithNamespace(namespace string) (helmclient.Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithNamespace", namespace)
+	ret0, _ := ret[0].(helmclient.Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WithNamespace indicates an expected call of WithNamespace.
+func (mr *MockClientMockRecorder) WithNamespace(namespace any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithNamespace", reflect.TypeOf((*MockClient)(nil).WithNamespace), namespace)
+}
+
 // MockRollBack is a mock of RollBack interface.
 type MockRollBack struct {
 	ctrl     *gomock.Controller
@@ -372,3 +918,40 @@ func (mr *MockRollBackMockRecorder) RollbackRelease(spec any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackRelease", reflect.TypeOf((*MockRollBack)(nil).RollbackRelease), spec)
 }
+
+// MockFailedReleaseRollBack is a mock of FailedReleaseRollBack interface.
+type MockFailedReleaseRollBack struct {
+	ctrl     *gomock.Controller
+	recorder *MockFailedReleaseRollBackMockRecorder
+}
+
+// MockFailedReleaseRollBackMockRecorder is the mock recorder for MockFailedReleaseRollBack.
+type MockFailedReleaseRollBackMockRecorder struct {
+	mock *MockFailedReleaseRollBack
+}
+
+// NewMockFailedReleaseRollBack creates a new mock instance.
+func NewMockFailedReleaseRollBack(ctrl *gomock.Controller) *MockFailedReleaseRollBack {
+	mock := &MockFailedReleaseRollBack{ctrl: ctrl}
+	mock.recorder = &MockFailedReleaseRollBackMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFailedReleaseRollBack) EXPECT() *MockFailedReleaseRollBackMockRecorder {
+	return m.recorder
+}
+
+// RollbackFailedRelease mocks base method.
+func (m *MockFailedReleaseRollBack) RollbackFailedRelease(ctx context.Context, spec *helmclient.ChartSpec, failed *release.Release, upgradeErr error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackFailedRelease", ctx, spec, failed, upgradeErr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollbackFailedRelease indicates an expected call of RollbackFailedRelease.
+func (mr *MockFailedReleaseRollBackMockRecorder) RollbackFailedRelease(ctx, spec, failed, upgradeErr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackFailedRelease", reflect.TypeOf((*MockFailedReleaseRollBack)(nil).RollbackFailedRelease), ctx, spec, failed, upgradeErr)
+}
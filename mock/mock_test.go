@@ -1,11 +1,13 @@
 package mockhelmclient
 
 import (
+	"context"
 	"testing"
 
 	"go.uber.org/mock/gomock"
 
 	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/repo"
 )
 
 var mockedRelease = release.Release{Name: "test"}
@@ -28,6 +30,21 @@ func TestHelmClientInterfaces(t *testing.T) {
 		}
 	})
 
+	t.Run("ListChartRepos", func(t *testing.T) {
+		mockClient.EXPECT().ListChartRepos().Return([]*repo.Entry{{Name: "stable"}})
+		if r := mockClient.ListChartRepos(); len(r) == 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("RemoveChartRepo", func(t *testing.T) {
+		mockClient.EXPECT().RemoveChartRepo("stable").Return(nil)
+		err := mockClient.RemoveChartRepo("stable")
+		if err != nil {
+			panic(err)
+		}
+	})
+
 	t.Run("ListReleases", func(t *testing.T) {
 		mockClient.EXPECT().ListDeployedReleases().Return([]*release.Release{&mockedRelease}, nil)
 		r, err := mockClient.ListDeployedReleases()
@@ -62,4 +79,16 @@ func TestHelmClientInterfaces(t *testing.T) {
 			panic(err)
 		}
 	})
+
+	t.Run("GetReleaseWithContext", func(t *testing.T) {
+		ctx := context.Background()
+		mockClient.EXPECT().GetReleaseWithContext(ctx, mockedRelease.Name).Return(&release.Release{Name: mockedRelease.Name}, nil)
+		r, err := mockClient.GetReleaseWithContext(ctx, mockedRelease.Name)
+		if err != nil {
+			panic(err)
+		}
+		if r == nil {
+			panic(err)
+		}
+	})
 }
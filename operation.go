@@ -0,0 +1,56 @@
+package helmclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// pendingOperationMessage is the message of helm's error for releases on which another operation is in progress.
+const pendingOperationMessage = "another operation (install/upgrade/rollback) is in progress"
+
+// ClassifyError returns the class of the provided error of an operation.
+// Cancellation and timeouts take precedence over the other classes, as they may wrap any error.
+func ClassifyError(err error) ErrorClass {
+	switch {
+	case err == nil:
+		return ErrorClassNone
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case errors.Is(err, context.DeadlineExceeded), wait.Interrupted(err):
+		return ErrorClassTimeout
+	case errors.Is(err, ErrReleaseNotFound), errors.Is(err, ErrChartRepoNotFound), apierrors.IsNotFound(err):
+		return ErrorClassNotFound
+	case errors.Is(err, ErrInvalidChartSpec), errors.Is(err, ErrUnsupportedChartType):
+		return ErrorClassInvalid
+	case errors.Is(err, ErrLintFailed):
+		return ErrorClassLint
+	case apierrors.IsConflict(err), apierrors.IsAlreadyExists(err), strings.Contains(err.Error(), pendingOperationMessage):
+		return ErrorClassConflict
+	default:
+		return ErrorClassOther
+	}
+}
+
+// observeOperation reports the provided operation, started at the provided time, to the client's operation observer.
+// It is deferred by operations with a pointer to their error, so that the error is read once the operation returned.
+func (c *HelmClient) observeOperation(event OperationEvent, start time.Time, err *error) {
+	if c.operationObserver == nil {
+		return
+	}
+
+	event.Duration = time.Since(start)
+	event.Err = *err
+	event.ErrorClass = ClassifyError(*err)
+
+	event.Outcome = OperationSucceeded
+	if *err != nil {
+		event.Outcome = OperationFailed
+	}
+
+	c.operationObserver.ObserveOperation(event)
+}
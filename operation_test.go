@@ -0,0 +1,116 @@
+package helmclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/repo"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestClassifyError(t *testing.T) {
+	configMaps := schema.GroupResource{Resource: "configmaps"}
+
+	tests := map[ErrorClass][]error{
+		ErrorClassNone:     {nil},
+		ErrorClassCanceled: {fmt.Errorf("failed: %w", context.Canceled)},
+		ErrorClassTimeout: {
+			&UpgradeError{Err: context.DeadlineExceeded},
+			wait.ErrorInterrupted(errors.New("timed out waiting for the condition")),
+		},
+		ErrorClassNotFound: {
+			&ReleaseNotFoundError{Name: "app"},
+			fmt.Errorf("%w: %q", ErrChartRepoNotFound, "stable"),
+			apierrors.NewNotFound(configMaps, "app"),
+		},
+		ErrorClassInvalid: {&InvalidChartSpecError{}, &UnsupportedChartTypeError{Chart: "lib", Type: "library"}},
+		ErrorClassLint:    {&LintError{}},
+		ErrorClassConflict: {
+			apierrors.NewAlreadyExists(configMaps, "app"),
+			errors.New("another operation (install/upgrade/rollback) is in progress"),
+		},
+		ErrorClassOther: {errors.New("boom")},
+	}
+
+	for class, errs := range tests {
+		for _, err := range errs {
+			if actual := ClassifyError(err); actual != class {
+				t.Errorf("expected the class %q for %v, got %q", class, err, actual)
+			}
+		}
+	}
+}
+
+func TestOperationObserver(t *testing.T) {
+	c := newTestClient(t)
+
+	var events []OperationEvent
+	c.operationObserver = OperationObserverFunc(func(event OperationEvent) {
+		events = append(events, event)
+	})
+
+	chartPath := writeTestChart(t, map[string]string{
+		"Chart.yaml":               "apiVersion: v2\nname: test-chart\nversion: 0.1.0\n",
+		"templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\n",
+	})
+
+	spec := &ChartSpec{ReleaseName: "app", ChartName: chartPath, Namespace: "default"}
+	if _, err := c.InstallChart(context.Background(), spec, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.UninstallReleaseByName("missing"); err == nil {
+		t.Fatal("expected an error for a missing release")
+	}
+
+	var operations []Operation
+	for _, event := range events {
+		operations = append(operations, event.Operation)
+	}
+
+	expected := []Operation{OperationChartFetch, OperationInstall, OperationUninstall}
+	if !reflect.DeepEqual(operations, expected) {
+		t.Fatalf("expected the operations %v, got %v", expected, operations)
+	}
+
+	install := events[1]
+	if install.ReleaseName != "app" || install.Chart != chartPath || install.Outcome != OperationSucceeded || install.Duration <= 0 {
+		t.Errorf("expected a successful install of 'app', got %+v", install)
+	}
+
+	uninstall := events[2]
+	if uninstall.Outcome != OperationFailed || uninstall.ErrorClass != ErrorClassNotFound || !errors.Is(uninstall.Err, ErrReleaseNotFound) {
+		t.Errorf("expected the uninstallation to fail with a not-found error, got %+v", uninstall)
+	}
+}
+
+func TestOperationObserverRepoAdd(t *testing.T) {
+	c := newTestClient(t)
+
+	var events []OperationEvent
+	c.operationObserver = OperationObserverFunc(func(event OperationEvent) {
+		events = append(events, event)
+	})
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+
+	if err := c.AddOrUpdateChartRepo(repo.Entry{Name: "missing", URL: server.URL}); err == nil {
+		t.Fatal("expected an error for a repository without an index")
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("expected a single event, got %+v", events)
+	}
+
+	if event := events[0]; event.Operation != OperationRepoAdd || event.Repository != "missing" || event.Outcome != OperationFailed {
+		t.Errorf("expected the failed addition of the repository 'missing', got %+v", event)
+	}
+}
@@ -0,0 +1,94 @@
+package helmclient
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/downloader"
+	"helm.sh/helm/v3/pkg/provenance"
+)
+
+// PackageChart packages the chart directory at 'chartPath' into a versioned chart archive, similar to 'helm package'.
+// It returns the path of the written archive. If signing is enabled, a provenance file is written next to it.
+func (c *HelmClient) PackageChart(ctx context.Context, chartPath string, opts *PackageChartOptions) (string, error) {
+	if opts == nil {
+		opts = &PackageChartOptions{}
+	}
+
+	stat, err := os.Stat(chartPath)
+	if err != nil {
+		return "", err
+	}
+
+	if !stat.IsDir() {
+		return "", fmt.Errorf("cannot package %q, must provide a chart directory", chartPath)
+	}
+
+	if opts.DependencyUpdate {
+		man := &downloader.Manager{
+			ChartPath:        chartPath,
+			Keyring:          opts.Keyring,
+			SkipUpdate:       false,
+			Getters:          contextProviders(ctx, c.Providers),
+			RegistryClient:   c.ActionConfig.RegistryClient,
+			RepositoryConfig: c.Settings.RepositoryConfig,
+			RepositoryCache:  c.Settings.RepositoryCache,
+			Out:              c.output,
+		}
+		if err := man.Update(); err != nil {
+			return "", fmt.Errorf("failed to update dependencies of chart %q: %w", chartPath, err)
+		}
+	}
+
+	destination := opts.Destination
+	if destination == "" {
+		destination = "."
+	}
+
+	client := action.NewPackage()
+	client.Version = opts.Version
+	client.AppVersion = opts.AppVersion
+	client.Destination = destination
+	client.RepositoryConfig = c.Settings.RepositoryConfig
+	client.RepositoryCache = c.Settings.RepositoryCache
+
+	chartArchive, err := client.Run(chartPath, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if opts.Sign {
+		if err := signChart(chartArchive, opts.Keyring, opts.Key, opts.Passphrase); err != nil {
+			return "", fmt.Errorf("failed to sign chart archive %q: %w", chartArchive, err)
+		}
+	}
+
+	c.DebugLog("packaged chart %q to %q", chartPath, chartArchive)
+
+	return chartArchive, nil
+}
+
+// signChart signs the chart archive with the key named 'key' from 'keyring' and writes the provenance file next to it.
+// Unlike action.Package.Clearsign, the passphrase of the key is passed directly instead of being prompted for.
+func signChart(chartArchive, keyring, key string, passphrase []byte) error {
+	signer, err := provenance.NewFromKeyring(keyring, key)
+	if err != nil {
+		return err
+	}
+
+	err = signer.DecryptKey(func(string) ([]byte, error) {
+		return passphrase, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	signature, err := signer.ClearSign(chartArchive)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(chartArchive+".prov", []byte(signature), 0o644)
+}
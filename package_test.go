@@ -0,0 +1,95 @@
+package helmclient
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/openpgp" //nolint:staticcheck // helm's provenance package is built on the deprecated openpgp package.
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/provenance"
+)
+
+// writeTestKeyring writes a keyring containing a new private signing key and returns its path.
+func writeTestKeyring(t *testing.T, name string) string {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity(name, "", name+"@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyring := filepath.Join(t.TempDir(), "secring.gpg")
+	file, err := os.Create(keyring)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if err := entity.SerializePrivate(file, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	return keyring
+}
+
+func TestPackageChart(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	dependencyPath := writeTestChart(t, map[string]string{
+		"Chart.yaml": "apiVersion: v2\nname: dependency\nversion: 1.0.0\n",
+	})
+
+	chartPath := writeTestChart(t, map[string]string{
+		"Chart.yaml": "apiVersion: v2\nname: test-chart\nversion: 0.1.0\nappVersion: 1.0.0\n" +
+			"dependencies:\n- name: dependency\n  version: 1.0.0\n  repository: file://" + dependencyPath + "\n",
+	})
+
+	keyring := writeTestKeyring(t, "packager")
+	destination := t.TempDir()
+
+	chartArchive, err := c.PackageChart(ctx, chartPath, &PackageChartOptions{
+		Version:          "0.2.0",
+		AppVersion:       "2.0.0",
+		Destination:      destination,
+		DependencyUpdate: true,
+		Sign:             true,
+		Keyring:          keyring,
+		Key:              "packager",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := filepath.Join(destination, "test-chart-0.2.0.tgz"); chartArchive != expected {
+		t.Errorf("expected the chart archive at %q, got %q", expected, chartArchive)
+	}
+
+	helmChart, err := loader.LoadFile(chartArchive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if helmChart.Metadata.AppVersion != "2.0.0" {
+		t.Errorf("expected the app version to be overridden, got %q", helmChart.Metadata.AppVersion)
+	}
+
+	if len(helmChart.Dependencies()) != 1 {
+		t.Errorf("expected the dependency to be packaged, got %d dependencies", len(helmChart.Dependencies()))
+	}
+
+	signatory, err := provenance.NewFromKeyring(keyring, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := signatory.Verify(chartArchive, chartArchive+".prov"); err != nil {
+		t.Errorf("expected a valid provenance file: %v", err)
+	}
+
+	if _, err := c.PackageChart(ctx, chartArchive, nil); err == nil {
+		t.Error("expected packaging a chart archive to fail")
+	}
+}
@@ -0,0 +1,246 @@
+package helmclient
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/kube"
+	"helm.sh/helm/v3/pkg/release"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// progressReporter reports the progress of installing or upgrading a release to the observer of GenericHelmOptions.
+// A nil reporter or a reporter without observer discards all events.
+type progressReporter struct {
+	observer    ProgressObserver
+	releaseName string
+}
+
+// newProgressReporter returns a reporter for the observer of the provided options, or nil if none is configured.
+func newProgressReporter(opts *GenericHelmOptions, releaseName string) *progressReporter {
+	if opts == nil || opts.ProgressObserver == nil {
+		return nil
+	}
+
+	return &progressReporter{observer: opts.ProgressObserver, releaseName: releaseName}
+}
+
+// report sends the provided event to the observer.
+func (r *progressReporter) report(event ProgressEvent) {
+	if r == nil {
+		return
+	}
+
+	event.ReleaseName = r.releaseName
+	event.Time = time.Now()
+
+	r.observer.Progress(event)
+}
+
+// actionConfig returns a copy of the provided action configuration whose kube client reports applied resources,
+// hooks and waits. Without an observer, the configuration is returned as is.
+func (r *progressReporter) actionConfig(actionConfig *action.Configuration) *action.Configuration {
+	if r == nil {
+		return actionConfig
+	}
+
+	observed := *actionConfig
+	observed.KubeClient = &progressKubeClient{Interface: actionConfig.KubeClient, progress: r}
+
+	return &observed
+}
+
+// progressKubeClient is a kube.Interface reporting the operations performed by helm actions.
+// The optional kube interfaces are passed through if the wrapped client implements them;
+// otherwise they fall back to the behaviour helm shows for clients not implementing them.
+type progressKubeClient struct {
+	kube.Interface
+	progress *progressReporter
+}
+
+var (
+	_ kube.InterfaceExt                 = &progressKubeClient{}
+	_ kube.InterfaceThreeWayMerge       = &progressKubeClient{}
+	_ kube.InterfaceLogs                = &progressKubeClient{}
+	_ kube.InterfaceDeletionPropagation = &progressKubeClient{}
+	_ kube.InterfaceResources           = &progressKubeClient{}
+)
+
+// Create reports the creation of hooks as started hooks and of all other resources as applied resources.
+func (k *progressKubeClient) Create(resources kube.ResourceList) (*kube.Result, error) {
+	hooks := hookNames(resources)
+	for _, hook := range hooks {
+		k.progress.report(ProgressEvent{Type: ProgressHookStarted, Hook: hook, Message: fmt.Sprintf("running hook %s", hook)})
+	}
+
+	result, err := k.Interface.Create(resources)
+	if err == nil && len(hooks) == 0 {
+		k.progress.report(ProgressEvent{
+			Type:      ProgressResourcesApplied,
+			Resources: len(resources),
+			Message:   fmt.Sprintf("created %d resources", len(resources)),
+		})
+	}
+
+	return result, err
+}
+
+// Update reports the updated resources as applied resources.
+func (k *progressKubeClient) Update(original, target kube.ResourceList, force bool) (*kube.Result, error) {
+	result, err := k.Interface.Update(original, target, force)
+	k.reportUpdate(target, err)
+
+	return result, err
+}
+
+// Wait reports the number of resources waited on.
+func (k *progressKubeClient) Wait(resources kube.ResourceList, timeout time.Duration) error {
+	k.reportWaiting(resources, timeout)
+
+	return k.Interface.Wait(resources, timeout)
+}
+
+// WaitWithJobs reports the number of resources waited on.
+func (k *progressKubeClient) WaitWithJobs(resources kube.ResourceList, timeout time.Duration) error {
+	k.reportWaiting(resources, timeout)
+
+	return k.Interface.WaitWithJobs(resources, timeout)
+}
+
+// WatchUntilReady is used by helm to wait for hooks, so its completion is reported as finished hooks.
+func (k *progressKubeClient) WatchUntilReady(resources kube.ResourceList, timeout time.Duration) error {
+	err := k.Interface.WatchUntilReady(resources, timeout)
+
+	for _, hook := range hookNames(resources) {
+		message := fmt.Sprintf("hook %s succeeded", hook)
+		if err != nil {
+			message = fmt.Sprintf("hook %s failed", hook)
+		}
+
+		k.progress.report(ProgressEvent{Type: ProgressHookFinished, Hook: hook, Message: message, Err: err})
+	}
+
+	return err
+}
+
+func (k *progressKubeClient) WaitForDelete(resources kube.ResourceList, timeout time.Duration) error {
+	if client, ok := k.Interface.(kube.InterfaceExt); ok {
+		return client.WaitForDelete(resources, timeout)
+	}
+
+	return nil
+}
+
+func (k *progressKubeClient) UpdateThreeWayMerge(original, target kube.ResourceList, force bool) (*kube.Result, error) {
+	client, ok := k.Interface.(kube.InterfaceThreeWayMerge)
+	if !ok {
+		return k.Update(original, target, force)
+	}
+
+	result, err := client.UpdateThreeWayMerge(original, target, force)
+	k.reportUpdate(target, err)
+
+	return result, err
+}
+
+func (k *progressKubeClient) GetPodList(namespace string, listOptions metav1.ListOptions) (*v1.PodList, error) {
+	if client, ok := k.Interface.(kube.InterfaceLogs); ok {
+		return client.GetPodList(namespace, listOptions)
+	}
+
+	return &v1.PodList{}, nil
+}
+
+func (k *progressKubeClient) OutputContainerLogsForPodList(podList *v1.PodList, namespace string, writerFunc func(namespace, pod, container string) io.Writer) error {
+	if client, ok := k.Interface.(kube.InterfaceLogs); ok {
+		return client.OutputContainerLogsForPodList(podList, namespace, writerFunc)
+	}
+
+	return nil
+}
+
+func (k *progressKubeClient) DeleteWithPropagationPolicy(resources kube.ResourceList, policy metav1.DeletionPropagation) (*kube.Result, []error) {
+	if client, ok := k.Interface.(kube.InterfaceDeletionPropagation); ok {
+		return client.DeleteWithPropagationPolicy(resources, policy)
+	}
+
+	return k.Delete(resources)
+}
+
+func (k *progressKubeClient) Get(resources kube.ResourceList, related bool) (map[string][]runtime.Object, error) {
+	client, ok := k.Interface.(kube.InterfaceResources)
+	if !ok {
+		return nil, fmt.Errorf("kube client %T does not support getting resources", k.Interface)
+	}
+
+	return client.Get(resources, related)
+}
+
+func (k *progressKubeClient) BuildTable(reader io.Reader, validate bool) (kube.ResourceList, error) {
+	client, ok := k.Interface.(kube.InterfaceResources)
+	if !ok {
+		return nil, fmt.Errorf("kube client %T does not support building tables", k.Interface)
+	}
+
+	return client.BuildTable(reader, validate)
+}
+
+// reportUpdate reports the resources applied by an update.
+func (k *progressKubeClient) reportUpdate(target kube.ResourceList, err error) {
+	if err != nil {
+		return
+	}
+
+	k.progress.report(ProgressEvent{
+		Type:      ProgressResourcesApplied,
+		Resources: len(target),
+		Message:   fmt.Sprintf("applied %d resources", len(target)),
+	})
+}
+
+// reportWaiting reports that helm waits for the provided resources to become ready.
+func (k *progressKubeClient) reportWaiting(resources kube.ResourceList, timeout time.Duration) {
+	k.progress.report(ProgressEvent{
+		Type:      ProgressWaiting,
+		Resources: len(resources),
+		Message:   fmt.Sprintf("waiting up to %s for %d resources to become ready", timeout, len(resources)),
+	})
+}
+
+// hookNames returns the names of the hook resources in the provided list.
+func hookNames(resources kube.ResourceList) []string {
+	var hooks []string
+	_ = resources.Visit(func(info *resource.Info, err error) error {
+		if err != nil || info.Object == nil {
+			return nil
+		}
+
+		accessor, err := meta.Accessor(info.Object)
+		if err != nil {
+			return nil
+		}
+
+		if _, ok := accessor.GetAnnotations()[release.HookAnnotation]; ok {
+			hooks = append(hooks, info.Name)
+		}
+
+		return nil
+	})
+
+	return hooks
+}
+
+// chartFetchedEvent returns the event reporting that the provided chart has been fetched.
+func chartFetchedEvent(helmChart *chart.Chart) ProgressEvent {
+	return ProgressEvent{
+		Type:    ProgressChartFetched,
+		Message: fmt.Sprintf("fetched chart %s-%s", helmChart.Metadata.Name, helmChart.Metadata.Version),
+	}
+}
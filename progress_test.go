@@ -0,0 +1,148 @@
+package helmclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/kube"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/kubernetes/scheme"
+	restfake "k8s.io/client-go/rest/fake"
+)
+
+// buildingKubeClient decodes manifests into unstructured resources instead of discarding them.
+// The resources are backed by an empty cluster, so helm finds no existing resources to adopt.
+type buildingKubeClient struct {
+	kubefake.PrintingKubeClient
+}
+
+// notFoundClient responds to every request with a NotFound status.
+var notFoundClient = &restfake.RESTClient{
+	NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+	Client: restfake.CreateHTTPClient(func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)),
+		}, nil
+	}),
+}
+
+func (c *buildingKubeClient) Build(reader io.Reader, _ bool) (kube.ResourceList, error) {
+	var resources kube.ResourceList
+
+	decoder := utilyaml.NewYAMLOrJSONDecoder(reader, 4096)
+	for {
+		object := &unstructured.Unstructured{}
+		if err := decoder.Decode(&object.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return resources, nil
+			}
+			return nil, err
+		}
+
+		if len(object.Object) == 0 {
+			continue
+		}
+
+		if object.GetNamespace() == "" {
+			object.SetNamespace("default")
+		}
+
+		gvk := object.GroupVersionKind()
+		resources = append(resources, &resource.Info{
+			Client: notFoundClient,
+			Mapping: &meta.RESTMapping{
+				Resource:         gvk.GroupVersion().WithResource(strings.ToLower(gvk.Kind) + "s"),
+				GroupVersionKind: gvk,
+				Scope:            meta.RESTScopeNamespace,
+			},
+			Namespace: object.GetNamespace(),
+			Name:      object.GetName(),
+			Object:    object,
+		})
+	}
+}
+
+func TestProgressObserver(t *testing.T) {
+	c := newTestClient(t)
+	c.ActionConfig.KubeClient = &buildingKubeClient{PrintingKubeClient: kubefake.PrintingKubeClient{Out: io.Discard}}
+
+	chartPath := writeTestChart(t, map[string]string{
+		"Chart.yaml":               "apiVersion: v2\nname: test-chart\nversion: 0.1.0\n",
+		"templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\n",
+		"templates/secret.yaml":    "apiVersion: v1\nkind: Secret\nmetadata:\n  name: test\n",
+		"templates/hook.yaml": "apiVersion: v1\nkind: Pod\nmetadata:\n  name: migrate\n" +
+			"  annotations:\n    helm.sh/hook: pre-install,pre-upgrade\n",
+	})
+
+	var events []ProgressEvent
+	opts := &GenericHelmOptions{
+		ProgressObserver: ProgressObserverFunc(func(event ProgressEvent) {
+			events = append(events, event)
+		}),
+	}
+
+	spec := &ChartSpec{
+		ReleaseName: "progress",
+		ChartName:   chartPath,
+		Namespace:   "default",
+		Wait:        true,
+	}
+
+	if _, err := c.InstallChart(context.Background(), spec, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ProgressEventType{
+		ProgressChartFetched,
+		ProgressHookStarted,
+		ProgressHookFinished,
+		ProgressResourcesApplied,
+		ProgressWaiting,
+	}
+
+	var types []ProgressEventType
+	for _, event := range events {
+		types = append(types, event.Type)
+
+		if event.ReleaseName != "progress" {
+			t.Errorf("expected the event %q of release 'progress', got %q", event.Type, event.ReleaseName)
+		}
+	}
+
+	if !reflect.DeepEqual(types, expected) {
+		t.Fatalf("expected the events %v, got %v", expected, types)
+	}
+
+	if events[1].Hook != "migrate" {
+		t.Errorf("expected the hook 'migrate' to be reported, got %q", events[1].Hook)
+	}
+
+	if events[3].Resources != 2 || events[4].Resources != 2 {
+		t.Errorf("expected 2 resources to be applied and waited on, got %d and %d", events[3].Resources, events[4].Resources)
+	}
+
+	events = nil
+	if _, err := c.UpgradeChart(context.Background(), spec, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	types = nil
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+
+	if !reflect.DeepEqual(types, expected) {
+		t.Fatalf("expected the upgrade events %v, got %v", expected, types)
+	}
+}
@@ -0,0 +1,102 @@
+// Package prometheushelmclient provides a Prometheus collector recording the operations of a helmclient.Client.
+package prometheushelmclient
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	helmclient "github.com/mittwald/go-helm-client"
+)
+
+// defaultBuckets span operations from a tenth of a second, e.g. rendering a template,
+// to several minutes, e.g. waiting for the resources of an upgrade to become ready.
+var defaultBuckets = prometheus.ExponentialBuckets(0.1, 2, 13)
+
+// CollectorOptions defines the options of a Collector.
+type CollectorOptions struct {
+	// Namespace is the prefix of the metric names. It defaults to "helmclient".
+	Namespace string
+	// ConstLabels are added to all metrics, e.g. to distinguish several clients.
+	ConstLabels prometheus.Labels
+	// Buckets are the buckets of the duration histogram in seconds. They default to 0.1s up to about 7 minutes.
+	Buckets []float64
+}
+
+// Collector is a helmclient.OperationObserver recording the operations of a client as Prometheus metrics:
+//
+//   - <namespace>_operation_duration_seconds is a histogram of the duration of all operations.
+//   - <namespace>_operation_errors_total counts failed operations.
+//
+// Both metrics are labeled by 'operation', 'outcome' and 'error_class'.
+// Release names are not used as labels to keep the cardinality independent of the number of releases.
+type Collector struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+var (
+	_ helmclient.OperationObserver = &Collector{}
+	_ prometheus.Collector         = &Collector{}
+)
+
+// NewCollector returns a Collector using the provided options.
+// It must be registered with a Prometheus registry and passed to the client via helmclient.Options.OperationObserver.
+func NewCollector(opts *CollectorOptions) *Collector {
+	if opts == nil {
+		opts = &CollectorOptions{}
+	}
+
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = "helmclient"
+	}
+
+	buckets := opts.Buckets
+	if len(buckets) == 0 {
+		buckets = defaultBuckets
+	}
+
+	labels := []string{"operation", "outcome", "error_class"}
+
+	return &Collector{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "operation_duration_seconds",
+			Help:        "Duration of the operations of the helm client.",
+			ConstLabels: opts.ConstLabels,
+			Buckets:     buckets,
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "operation_errors_total",
+			Help:        "Number of failed operations of the helm client.",
+			ConstLabels: opts.ConstLabels,
+		}, labels),
+	}
+}
+
+// ObserveOperation records the provided operation.
+func (c *Collector) ObserveOperation(event helmclient.OperationEvent) {
+	labels := prometheus.Labels{
+		"operation":   string(event.Operation),
+		"outcome":     string(event.Outcome),
+		"error_class": string(event.ErrorClass),
+	}
+
+	c.duration.With(labels).Observe(event.Duration.Seconds())
+
+	if event.Outcome == helmclient.OperationFailed {
+		c.errors.With(labels).Inc()
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.duration.Describe(ch)
+	c.errors.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.duration.Collect(ch)
+	c.errors.Collect(ch)
+}
@@ -0,0 +1,56 @@
+package prometheushelmclient
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	helmclient "github.com/mittwald/go-helm-client"
+)
+
+func TestCollector(t *testing.T) {
+	collector := NewCollector(&CollectorOptions{ConstLabels: prometheus.Labels{"cluster": "test"}})
+
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(collector); err != nil {
+		t.Fatal(err)
+	}
+
+	collector.ObserveOperation(helmclient.OperationEvent{
+		Operation:   helmclient.OperationInstall,
+		ReleaseName: "app",
+		Duration:    2 * time.Second,
+		Outcome:     helmclient.OperationSucceeded,
+	})
+
+	collector.ObserveOperation(helmclient.OperationEvent{
+		Operation:   helmclient.OperationUpgrade,
+		ReleaseName: "app",
+		Duration:    5 * time.Minute,
+		Outcome:     helmclient.OperationFailed,
+		ErrorClass:  helmclient.ErrorClassTimeout,
+		Err:         errors.New("timed out waiting for the condition"),
+	})
+
+	if count := testutil.CollectAndCount(collector, "helmclient_operation_duration_seconds"); count != 2 {
+		t.Errorf("expected 2 duration series, got %d", count)
+	}
+
+	expected := `
+# HELP helmclient_operation_errors_total Number of failed operations of the helm client.
+# TYPE helmclient_operation_errors_total counter
+helmclient_operation_errors_total{cluster="test",error_class="timeout",operation="upgrade",outcome="failure"} 1
+`
+
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "helmclient_operation_errors_total"); err != nil {
+		t.Error(err)
+	}
+
+	if problems, err := testutil.GatherAndLint(registry); err != nil || len(problems) > 0 {
+		t.Errorf("expected the metrics to follow the Prometheus conventions, got %v %v", problems, err)
+	}
+}
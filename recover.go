@@ -0,0 +1,108 @@
+package helmclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+const defaultRecoveryMinAge = 5 * time.Minute
+
+// RecoverRelease recovers the release with the provided name if its latest revision is stuck in a pending state,
+// e.g. because the process installing or upgrading it was killed. Until then, helm refuses to operate on the release,
+// as another operation seems to be in progress.
+// Only revisions older than the configured minimum age are recovered, as younger ones may belong to an operation
+// that is still running. Recovery is not coordinated with other clients, so the minimum age should exceed
+// the timeout of any operation performed on the release.
+// The returned ReleaseRecovery reports what was done, even if rolling back the release failed.
+func (c *HelmClient) RecoverRelease(ctx context.Context, name string, opts *RecoverReleaseOptions) (*ReleaseRecovery, error) {
+	if opts == nil {
+		opts = &RecoverReleaseOptions{}
+	}
+
+	minAge := defaultRecoveryMinAge
+	if opts.MinAge > 0 {
+		minAge = opts.MinAge
+	}
+
+	rel, err := c.ActionConfig.Releases.Last(name)
+	if err != nil {
+		return nil, releaseError(name, err)
+	}
+
+	recovery := &ReleaseRecovery{
+		ReleaseName: rel.Name,
+		Namespace:   rel.Namespace,
+		Action:      RecoveryNone,
+		Revision:    rel.Version,
+		Status:      rel.Info.Status,
+		Age:         time.Since(rel.Info.LastDeployed.Time),
+	}
+
+	if !rel.Info.Status.IsPending() || recovery.Age < minAge {
+		return recovery, nil
+	}
+
+	rel.SetStatus(release.StatusFailed, fmt.Sprintf("Marked as failed after being %s for %s", recovery.Status, recovery.Age.Round(time.Second)))
+	if err := c.ActionConfig.Releases.Update(rel); err != nil {
+		return nil, fmt.Errorf("failed to mark revision %d of release %q as failed: %w", rel.Version, name, err)
+	}
+
+	recovery.Action = RecoveryMarkedFailed
+	c.DebugLog("%s", recovery)
+
+	if opts.Strategy != RecoverRollback {
+		return recovery, nil
+	}
+
+	deployed, err := c.ActionConfig.Releases.Deployed(name)
+	if errors.Is(err, driver.ErrNoDeployedReleases) {
+		return recovery, nil
+	}
+	if err != nil {
+		return recovery, err
+	}
+
+	client := action.NewRollback(c.ActionConfig)
+	client.Version = deployed.Version
+	client.Timeout = timeoutWithContext(ctx, client.Timeout)
+
+	_, err = runWithContext(ctx, func() (struct{}, error) {
+		return struct{}{}, client.Run(name)
+	})
+	if err != nil {
+		return recovery, fmt.Errorf("failed to roll back release %q to revision %d: %w", name, deployed.Version, releaseError(name, err))
+	}
+
+	recovery.Action = RecoveryRolledBack
+	recovery.RollbackRevision = deployed.Version
+	c.DebugLog("%s", recovery)
+
+	return recovery, nil
+}
+
+// recoverPendingRelease recovers the release of the provided spec before it is upgraded, if enabled via the provided options.
+func (c *HelmClient) recoverPendingRelease(ctx context.Context, spec *ChartSpec, opts *GenericHelmOptions) error {
+	if opts == nil || opts.RecoverPendingRelease == nil {
+		return nil
+	}
+
+	recovery, err := c.RecoverRelease(ctx, spec.ReleaseName, opts.RecoverPendingRelease)
+	if errors.Is(err, ErrReleaseNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if recovery.Action != RecoveryNone {
+		newProgressReporter(opts, spec.ReleaseName).report(ProgressEvent{Type: ProgressReleaseRecovered, Message: recovery.String()})
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package helmclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	helmtime "helm.sh/helm/v3/pkg/time"
+)
+
+// createTestRevision stores a revision of the release 'app' with the provided status, created the provided duration ago.
+func createTestRevision(t *testing.T, c *HelmClient, version int, status release.Status, age time.Duration) {
+	t.Helper()
+
+	err := c.ActionConfig.Releases.Create(&release.Release{
+		Name:      "app",
+		Namespace: "default",
+		Version:   version,
+		Chart:     &chart.Chart{Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "app", Version: "0.1.0"}},
+		Info:      &release.Info{Status: status, LastDeployed: helmtime.Time{Time: time.Now().Add(-age)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecoverRelease(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not stale", func(t *testing.T) {
+		c := newTestClient(t)
+		createTestRevision(t, c, 1, release.StatusDeployed, time.Hour)
+		createTestRevision(t, c, 2, release.StatusPendingUpgrade, time.Minute)
+
+		recovery, err := c.RecoverRelease(ctx, "app", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if recovery.Action != RecoveryNone || recovery.Revision != 2 || recovery.Status != release.StatusPendingUpgrade {
+			t.Fatalf("expected the young pending revision to be left untouched, got %+v", recovery)
+		}
+	})
+
+	t.Run("mark failed", func(t *testing.T) {
+		c := newTestClient(t)
+		createTestRevision(t, c, 1, release.StatusDeployed, time.Hour)
+		createTestRevision(t, c, 2, release.StatusPendingUpgrade, time.Minute)
+
+		recovery, err := c.RecoverRelease(ctx, "app", &RecoverReleaseOptions{MinAge: 30 * time.Second})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if recovery.Action != RecoveryMarkedFailed || recovery.Revision != 2 {
+			t.Fatalf("expected revision 2 to be marked as failed, got %+v", recovery)
+		}
+
+		rel, err := c.ActionConfig.Releases.Last("app")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if rel.Version != 2 || rel.Info.Status != release.StatusFailed {
+			t.Errorf("expected revision 2 to have failed, got revision %d %s", rel.Version, rel.Info.Status)
+		}
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		c := newTestClient(t)
+		createTestRevision(t, c, 1, release.StatusSuperseded, 2*time.Hour)
+		createTestRevision(t, c, 2, release.StatusDeployed, time.Hour)
+		createTestRevision(t, c, 3, release.StatusPendingRollback, 10*time.Minute)
+
+		recovery, err := c.RecoverRelease(ctx, "app", &RecoverReleaseOptions{Strategy: RecoverRollback})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if recovery.Action != RecoveryRolledBack || recovery.Revision != 3 || recovery.RollbackRevision != 2 {
+			t.Fatalf("expected revision 3 to be rolled back to revision 2, got %+v", recovery)
+		}
+
+		history, err := c.ActionConfig.Releases.History("app")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		statuses := map[int]release.Status{}
+		for _, rel := range history {
+			statuses[rel.Version] = rel.Info.Status
+		}
+
+		if statuses[3] != release.StatusFailed || statuses[4] != release.StatusDeployed {
+			t.Errorf("expected revision 3 to have failed and revision 4 to be deployed, got %v", statuses)
+		}
+	})
+
+	t.Run("rollback without deployed revision", func(t *testing.T) {
+		c := newTestClient(t)
+		createTestRevision(t, c, 1, release.StatusPendingInstall, time.Hour)
+
+		recovery, err := c.RecoverRelease(ctx, "app", &RecoverReleaseOptions{Strategy: RecoverRollback})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if recovery.Action != RecoveryMarkedFailed {
+			t.Fatalf("expected the pending installation to be marked as failed, got %+v", recovery)
+		}
+	})
+
+	t.Run("missing release", func(t *testing.T) {
+		c := newTestClient(t)
+
+		if _, err := c.RecoverRelease(ctx, "app", nil); !errors.Is(err, ErrReleaseNotFound) {
+			t.Fatalf("expected ErrReleaseNotFound, got %v", err)
+		}
+	})
+}
+
+func TestInstallOrUpgradeChartRecoversPendingRelease(t *testing.T) {
+	c := newTestClient(t)
+	createTestRevision(t, c, 1, release.StatusPendingInstall, time.Hour)
+
+	spec := &ChartSpec{
+		ReleaseName: "app",
+		Namespace:   "default",
+		ChartName: writeTestChart(t, map[string]string{
+			"Chart.yaml": "apiVersion: v2\nname: app\nversion: 0.1.0\n",
+		}),
+	}
+
+	if _, err := c.InstallOrUpgradeChart(context.Background(), spec, nil); err == nil || !strings.Contains(err.Error(), "in progress") {
+		t.Fatalf("expected the pending release to block the upgrade, got %v", err)
+	}
+
+	var events []ProgressEvent
+	opts := &GenericHelmOptions{
+		RecoverPendingRelease: &RecoverReleaseOptions{},
+		ProgressObserver: ProgressObserverFunc(func(event ProgressEvent) {
+			events = append(events, event)
+		}),
+	}
+
+	rel, err := c.InstallOrUpgradeChart(context.Background(), spec, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rel.Version != 2 || rel.Info.Status != release.StatusDeployed {
+		t.Errorf("expected revision 2 to be deployed, got revision %d %s", rel.Version, rel.Info.Status)
+	}
+
+	if len(events) == 0 || events[0].Type != ProgressReleaseRecovered {
+		t.Fatalf("expected the recovery to be reported first, got %v", events)
+	}
+
+	if !strings.Contains(events[0].Message, "marked revision 1") {
+		t.Errorf("expected the recovery to be described, got %q", events[0].Message)
+	}
+}
@@ -0,0 +1,398 @@
+package helmclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/downloader"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/registry"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+)
+
+// registryCredentials holds the registry credentials of a client in memory.
+// Registries without in-memory credentials fall back to the registry config file and the docker credential store.
+type registryCredentials struct {
+	mu          sync.RWMutex
+	credentials map[string]auth.Credential
+	fallback    credentials.Store
+}
+
+// newRegistryCredentials returns registry credentials falling back to the registry config file at 'configPath'.
+func newRegistryCredentials(configPath string) *registryCredentials {
+	storeOptions := credentials.StoreOptions{DetectDefaultNativeStore: true}
+
+	var fallback credentials.Store
+	if store, err := credentials.NewStore(configPath, storeOptions); err == nil {
+		fallback = store
+	}
+
+	if dockerStore, err := credentials.NewStoreFromDocker(storeOptions); err == nil {
+		if fallback == nil {
+			fallback = dockerStore
+		} else {
+			fallback = credentials.NewStoreWithFallbacks(fallback, dockerStore)
+		}
+	}
+
+	return &registryCredentials{credentials: map[string]auth.Credential{}, fallback: fallback}
+}
+
+// Credential returns the credential of the registry at 'hostport'. It satisfies the signature of auth.Client.Credential.
+func (r *registryCredentials) Credential(ctx context.Context, hostport string) (auth.Credential, error) {
+	r.mu.RLock()
+	cred, ok := r.credentials[registryHost(hostport)]
+	r.mu.RUnlock()
+
+	if ok {
+		return cred, nil
+	}
+
+	if r.fallback == nil {
+		return auth.EmptyCredential, nil
+	}
+
+	return credentials.Credential(r.fallback)(ctx, hostport)
+}
+
+// Set stores the credential of the provided registry host.
+func (r *registryCredentials) Set(host string, cred auth.Credential) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.credentials[registryHost(host)] = cred
+}
+
+// Delete removes the credential of the provided registry host. It returns false if no credential was stored.
+func (r *registryCredentials) Delete(host string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	host = registryHost(host)
+	if _, ok := r.credentials[host]; !ok {
+		return false
+	}
+
+	delete(r.credentials, host)
+
+	return true
+}
+
+// registryHost returns the host of the provided registry reference, e.g. 'registry.example.com:5000'
+// for 'oci://registry.example.com:5000/charts'.
+func registryHost(ref string) string {
+	for _, scheme := range []string{registry.OCIScheme + "://", "https://", "http://"} {
+		ref = strings.TrimPrefix(ref, scheme)
+	}
+
+	host, _, _ := strings.Cut(ref, "/")
+	if host == "docker.io" {
+		// The docker hub API is served by a different host than the one used in references.
+		return "registry-1.docker.io"
+	}
+
+	return host
+}
+
+// newRegistryClient returns a registry client using the client's registry credentials and the provided connection options.
+func (c *HelmClient) newRegistryClient(opts *RegistryOptions) (*registry.Client, error) {
+	return c.newRegistryClientWithCredential(opts, c.registryCredentials.Credential)
+}
+
+// newChartSpecRegistryClient returns a registry client using the inline registry settings of the provided ChartSpec 'spec'.
+// The credentials of the spec are only passed to the chart's registry, unless PassCredentialsAll is set.
+func (c *HelmClient) newChartSpecRegistryClient(spec *ChartSpec) (*registry.Client, error) {
+	credential := c.registryCredentials.Credential
+	if spec.Username != "" || spec.Password != "" {
+		host := registryHost(spec.ChartName)
+		specCredential := auth.Credential{Username: spec.Username, Password: spec.Password}
+
+		credential = func(ctx context.Context, hostport string) (auth.Credential, error) {
+			if spec.PassCredentialsAll || registryHost(hostport) == host {
+				return specCredential, nil
+			}
+
+			return c.registryCredentials.Credential(ctx, hostport)
+		}
+	}
+
+	return c.newRegistryClientWithCredential(&RegistryOptions{
+		PlainHTTP:             spec.PlainHTTP,
+		InsecureSkipTLSVerify: spec.InsecureSkipTLSVerify,
+		CertFile:              spec.CertFile,
+		KeyFile:               spec.KeyFile,
+		CAFile:                spec.CAFile,
+	}, credential)
+}
+
+// newRegistryClientWithCredential returns a registry client resolving credentials via 'credential'.
+func (c *HelmClient) newRegistryClientWithCredential(opts *RegistryOptions, credential auth.CredentialFunc) (*registry.Client, error) {
+	if opts == nil {
+		opts = &RegistryOptions{}
+	}
+
+	httpClient, err := newRegistryHTTPClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	clientOptions := []registry.ClientOption{
+		registry.ClientOptDebug(c.Settings.Debug),
+		registry.ClientOptCredentialsFile(c.Settings.RegistryConfig),
+		registry.ClientOptHTTPClient(httpClient),
+		registry.ClientOptAuthorizer(auth.Client{
+			Client:     httpClient,
+			Cache:      auth.NewCache(),
+			Credential: credential,
+		}),
+	}
+
+	if opts.PlainHTTP {
+		clientOptions = append(clientOptions, registry.ClientOptPlainHTTP())
+	}
+
+	return registry.NewClient(clientOptions...)
+}
+
+// newRegistryHTTPClient returns an HTTP client using the TLS settings of the provided options.
+func newRegistryHTTPClient(opts *RegistryOptions) (*http.Client, error) {
+	if opts.CertFile == "" && opts.KeyFile == "" && opts.CAFile == "" && !opts.InsecureSkipTLSVerify {
+		return http.DefaultClient, nil
+	}
+
+	tlsConfig, err := newTLSConfig(opts.CertFile, opts.KeyFile, opts.CAFile, opts.InsecureSkipTLSVerify)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// newTLSConfig returns a TLS client configuration using the provided client certificate and CA bundle.
+func newTLSConfig(certFile, keyFile, caFile string, insecureSkipTLSVerify bool) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// #nosec G402 -- skipping the verification is an explicit opt-in of the caller.
+		InsecureSkipVerify: insecureSkipTLSVerify,
+	}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if caFile != "" {
+		ca, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file %q: %w", caFile, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("failed to parse CA file %q", caFile)
+		}
+
+		config.RootCAs = pool
+	}
+
+	return config, nil
+}
+
+// RegistryLogin verifies the provided credentials against the OCI registry at 'host' and stores them in memory.
+// The credentials are used by all registry operations of the client, including installing charts from OCI
+// references, but are neither written to the registry config file nor shared with other clients.
+func (c *HelmClient) RegistryLogin(ctx context.Context, host string, opts *RegistryLoginOptions) error {
+	if opts == nil {
+		opts = &RegistryLoginOptions{}
+	}
+
+	httpClient, err := newRegistryHTTPClient(&opts.RegistryOptions)
+	if err != nil {
+		return err
+	}
+
+	reg, err := remote.NewRegistry(registryHost(host))
+	if err != nil {
+		return err
+	}
+
+	cred := auth.Credential{Username: opts.Username, Password: opts.Password}
+	authClient := &auth.Client{
+		Client:             httpClient,
+		Cache:              auth.NewCache(),
+		Credential:         auth.StaticCredential(reg.Reference.Registry, cred),
+		ForceAttemptOAuth2: true,
+	}
+
+	reg.PlainHTTP = opts.PlainHTTP
+	reg.Client = authClient
+
+	if err := reg.Ping(ctx); err != nil {
+		// Registries not supporting OAuth2 are retried with basic authentication, like 'helm registry login' does.
+		authClient.ForceAttemptOAuth2 = false
+		if err := reg.Ping(ctx); err != nil {
+			return fmt.Errorf("failed to log in to registry %q: %w", host, err)
+		}
+	}
+
+	c.registryCredentials.Set(host, cred)
+	c.DebugLog("logged in to registry %q", host)
+
+	return nil
+}
+
+// RegistryLogout removes the in-memory credentials of the OCI registry at 'host'.
+func (c *HelmClient) RegistryLogout(host string) error {
+	if !c.registryCredentials.Delete(host) {
+		return fmt.Errorf("%w: %q", ErrRegistryNotLoggedIn, host)
+	}
+
+	c.DebugLog("logged out of registry %q", host)
+
+	return nil
+}
+
+// PushChart pushes the packaged chart at 'chartArchive' to the OCI repository 'remote', e.g. 'oci://registry.example.com/charts'.
+// The chart is pushed as '<remote>/<name>:<version>'. A provenance file next to the archive is pushed along with it.
+func (c *HelmClient) PushChart(ctx context.Context, chartArchive string, remote string, opts *RegistryOptions) (*registry.PushResult, error) {
+	if !registry.IsOCI(remote) {
+		return nil, fmt.Errorf("remote %q is not an OCI reference", remote)
+	}
+
+	stat, err := os.Stat(chartArchive)
+	if err != nil {
+		return nil, err
+	}
+
+	if stat.IsDir() {
+		return nil, fmt.Errorf("cannot push directory %q, must provide a chart archive", chartArchive)
+	}
+
+	helmChart, err := loader.LoadFile(chartArchive)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(chartArchive)
+	if err != nil {
+		return nil, err
+	}
+
+	pushOptions := []registry.PushOption{
+		registry.PushOptCreationTime(stat.ModTime().Format(time.RFC3339)),
+	}
+
+	provenance, err := os.ReadFile(chartArchive + ".prov")
+	switch {
+	case err == nil:
+		pushOptions = append(pushOptions, registry.PushOptProvData(provenance))
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	registryClient, err := c.newRegistryClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := fmt.Sprintf("%s:%s",
+		path.Join(strings.TrimPrefix(remote, registry.OCIScheme+"://"), helmChart.Name()),
+		helmChart.Metadata.Version,
+	)
+
+	return runWithContext(ctx, func() (*registry.PushResult, error) {
+		return registryClient.Push(data, ref, pushOptions...)
+	})
+}
+
+// PullChart downloads the chart at the OCI reference 'ref', e.g. 'oci://registry.example.com/charts/nginx'.
+// It returns the path of the downloaded chart archive, or of the chart directory if the chart was untarred.
+// If ctx is done before the pull completed, ctx.Err() is returned immediately and the pull is abandoned.
+// It keeps running in the background, but neither saves nor expands the chart once the download was cancelled.
+func (c *HelmClient) PullChart(ctx context.Context, ref string, opts *PullChartOptions) (string, error) {
+	if opts == nil {
+		opts = &PullChartOptions{}
+	}
+
+	if !registry.IsOCI(ref) {
+		return "", fmt.Errorf("chart reference %q is not an OCI reference", ref)
+	}
+
+	registryClient, err := c.newRegistryClient(&opts.RegistryOptions)
+	if err != nil {
+		return "", err
+	}
+
+	destDir := opts.DestDir
+	if destDir == "" {
+		destDir = "."
+	}
+
+	chartDownloader := downloader.ChartDownloader{
+		Out:              c.output,
+		Verify:           downloader.VerifyNever,
+		Getters:          contextProviders(ctx, c.Providers),
+		Options:          []getter.Option{getter.WithRegistryClient(registryClient)},
+		RegistryClient:   registryClient,
+		RepositoryConfig: c.Settings.RepositoryConfig,
+		RepositoryCache:  c.Settings.RepositoryCache,
+	}
+
+	if !opts.Untar {
+		return runWithContext(ctx, func() (string, error) {
+			saved, _, err := chartDownloader.DownloadTo(ref, opts.Version, destDir)
+			return saved, err
+		})
+	}
+
+	// The temporary download directory is owned by the pull itself, so that it is not removed
+	// while a pull that was abandoned due to the cancellation of ctx still writes to it.
+	return runWithContext(ctx, func() (string, error) {
+		downloadDir, err := os.MkdirTemp("", "helmclient-pull-")
+		if err != nil {
+			return "", err
+		}
+		defer os.RemoveAll(downloadDir)
+
+		chartArchive, _, err := chartDownloader.DownloadTo(ref, opts.Version, downloadDir)
+		if err != nil {
+			return "", err
+		}
+
+		helmChart, err := loader.LoadFile(chartArchive)
+		if err != nil {
+			return "", err
+		}
+
+		// Do not expand the chart if the caller stopped waiting for it.
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		if err := chartutil.ExpandFile(destDir, chartArchive); err != nil {
+			return "", err
+		}
+
+		return filepath.Join(destDir, helmChart.Name()), nil
+	})
+}
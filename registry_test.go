@@ -0,0 +1,356 @@
+package helmclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// testRegistry is a minimal OCI distribution registry requiring basic authentication.
+type testRegistry struct {
+	username string
+	password string
+
+	mu        sync.Mutex
+	uploads   int
+	blobs     map[string][]byte
+	manifests map[string]testManifest
+	tags      map[string][]string
+}
+
+type testManifest struct {
+	mediaType string
+	digest    string
+	data      []byte
+}
+
+func newTestRegistry(t *testing.T, username, password string) *httptest.Server {
+	t.Helper()
+
+	reg := &testRegistry{
+		username:  username,
+		password:  password,
+		blobs:     map[string][]byte{},
+		manifests: map[string]testManifest{},
+		tags:      map[string][]string{},
+	}
+
+	server := httptest.NewServer(reg)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func (reg *testRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if username, password, ok := r.BasicAuth(); !ok || username != reg.username || password != reg.password {
+		w.Header().Set("WWW-Authenticate", `Basic realm="test-registry"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	p := strings.TrimPrefix(r.URL.Path, "/v2/")
+	switch {
+	case p == "":
+		w.WriteHeader(http.StatusOK)
+	case strings.HasSuffix(p, "/tags/list"):
+		name := strings.TrimSuffix(p, "/tags/list")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"name": name, "tags": reg.tags[name]})
+	case strings.Contains(p, "/blobs/uploads/"):
+		reg.serveUpload(w, r, p)
+	case strings.Contains(p, "/blobs/"):
+		_, digest, _ := strings.Cut(p, "/blobs/")
+		data, ok := reg.blobs[digest]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeTestContent(w, r, "application/octet-stream", digest, data)
+	case strings.Contains(p, "/manifests/"):
+		reg.serveManifest(w, r, p)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (reg *testRegistry) serveUpload(w http.ResponseWriter, r *http.Request, p string) {
+	switch r.Method {
+	case http.MethodPost:
+		reg.uploads++
+		w.Header().Set("Location", fmt.Sprintf("/v2/%s%d", p, reg.uploads))
+		w.WriteHeader(http.StatusAccepted)
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		digest := r.URL.Query().Get("digest")
+		if digest != testDigest(data) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		reg.blobs[digest] = data
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (reg *testRegistry) serveManifest(w http.ResponseWriter, r *http.Request, p string) {
+	name, ref, _ := strings.Cut(p, "/manifests/")
+	if r.Method == http.MethodPut {
+		data, _ := io.ReadAll(r.Body)
+		manifest := testManifest{mediaType: r.Header.Get("Content-Type"), digest: testDigest(data), data: data}
+		reg.manifests[name+"@"+manifest.digest] = manifest
+		if !strings.HasPrefix(ref, "sha256:") {
+			reg.manifests[name+":"+ref] = manifest
+			reg.tags[name] = append(reg.tags[name], ref)
+		}
+		w.Header().Set("Docker-Content-Digest", manifest.digest)
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+
+	manifest, ok := reg.manifests[name+":"+ref]
+	if !ok {
+		manifest, ok = reg.manifests[name+"@"+ref]
+	}
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	writeTestContent(w, r, manifest.mediaType, manifest.digest, manifest.data)
+}
+
+func writeTestContent(w http.ResponseWriter, r *http.Request, mediaType, digest string, data []byte) {
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Docker-Content-Digest", digest)
+	w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(data)
+	}
+}
+
+func testDigest(data []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+}
+
+func TestRegistryPushAndPull(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	server := newTestRegistry(t, "user", "secret")
+	host := strings.TrimPrefix(server.URL, "http://")
+	registryOptions := RegistryOptions{PlainHTTP: true}
+
+	err := c.RegistryLogin(ctx, host, &RegistryLoginOptions{RegistryOptions: registryOptions, Username: "user", Password: "wrong"})
+	if err == nil {
+		t.Fatal("expected the login with invalid credentials to fail")
+	}
+
+	err = c.RegistryLogin(ctx, host, &RegistryLoginOptions{RegistryOptions: registryOptions, Username: "user", Password: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chartPath := writeTestChart(t, map[string]string{
+		"Chart.yaml":               "apiVersion: v2\nname: test-chart\nversion: 0.1.0\n",
+		"templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\n",
+	})
+
+	helmChart, err := loader.LoadDir(chartPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chartArchive, err := chartutil.Save(helmChart, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := c.PushChart(ctx, chartArchive, "oci://"+host+"/charts", &registryOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := host + "/charts/test-chart:0.1.0"; result.Ref != expected {
+		t.Errorf("expected the chart to be pushed as %q, got %q", expected, result.Ref)
+	}
+
+	destDir := t.TempDir()
+	pulled, err := c.PullChart(ctx, "oci://"+host+"/charts/test-chart", &PullChartOptions{RegistryOptions: registryOptions, DestDir: destDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := filepath.Join(destDir, "test-chart-0.1.0.tgz"); pulled != expected {
+		t.Errorf("expected the chart archive at %q, got %q", expected, pulled)
+	}
+
+	untarred, err := c.PullChart(ctx, "oci://"+host+"/charts/test-chart", &PullChartOptions{RegistryOptions: registryOptions, DestDir: destDir, Version: "0.1.0", Untar: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(untarred, "Chart.yaml")); err != nil {
+		t.Errorf("expected the chart to be untarred: %v", err)
+	}
+
+	if err := c.RegistryLogout(host); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.PullChart(ctx, "oci://"+host+"/charts/test-chart", &PullChartOptions{RegistryOptions: registryOptions, DestDir: destDir}); err == nil {
+		t.Error("expected pulling without credentials to fail")
+	}
+
+	if err := c.RegistryLogout(host); !errors.Is(err, ErrRegistryNotLoggedIn) {
+		t.Errorf("expected ErrRegistryNotLoggedIn, got %v", err)
+	}
+}
+
+func TestPullChartCancelled(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+
+	server := newTestRegistry(t, "user", "secret")
+	registryOptions := RegistryOptions{PlainHTTP: true}
+
+	chartPath := writeTestChart(t, map[string]string{
+		"Chart.yaml": "apiVersion: v2\nname: test-chart\nversion: 0.1.0\n",
+	})
+
+	helmChart, err := loader.LoadDir(chartPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chartArchive, err := chartutil.Save(helmChart, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serverHost := strings.TrimPrefix(server.URL, "http://")
+	if err := c.RegistryLogin(ctx, serverHost, &RegistryLoginOptions{RegistryOptions: registryOptions, Username: "user", Password: "secret"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.PushChart(ctx, chartArchive, "oci://"+serverHost+"/charts", &registryOptions); err != nil {
+		t.Fatal(err)
+	}
+
+	// Pull via a proxy holding back blob downloads until they are released.
+	blobRequested := make(chan struct{}, 1)
+	releaseBlobs := make(chan struct{})
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/blobs/") && r.Method == http.MethodGet {
+			select {
+			case blobRequested <- struct{}{}:
+			default:
+			}
+			<-releaseBlobs
+		}
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(proxy.Close)
+
+	host := strings.TrimPrefix(proxy.URL, "http://")
+	if err := c.RegistryLogin(ctx, host, &RegistryLoginOptions{RegistryOptions: registryOptions, Username: "user", Password: "secret"}); err != nil {
+		t.Fatal(err)
+	}
+
+	pullCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		<-blobRequested
+		cancel()
+	}()
+
+	destDir := t.TempDir()
+	_, err = c.PullChart(pullCtx, "oci://"+host+"/charts/test-chart", &PullChartOptions{RegistryOptions: registryOptions, DestDir: destDir, Version: "0.1.0", Untar: true})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the pull to be cancelled, got %v", err)
+	}
+
+	// Let the abandoned pull complete. It must remove its temporary directory without expanding the chart.
+	close(releaseBlobs)
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		entries, err := os.ReadDir(tempDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the temporary download directory to be removed, got %v", entries)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if entries, _ := os.ReadDir(destDir); len(entries) != 0 {
+		t.Errorf("expected the chart not to be expanded after the pull was cancelled, got %v", entries)
+	}
+}
+
+func TestInstallChartFromRegistryWithInlineCredentials(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	server := newTestRegistry(t, "user", "secret")
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	chartPath := writeTestChart(t, map[string]string{
+		"Chart.yaml":               "apiVersion: v2\nname: test-chart\nversion: 0.1.0\n",
+		"templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\n",
+	})
+
+	chartArchive, err := c.PackageChart(ctx, chartPath, &PackageChartOptions{Destination: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loginOptions := &RegistryLoginOptions{RegistryOptions: RegistryOptions{PlainHTTP: true}, Username: "user", Password: "secret"}
+	if err := c.RegistryLogin(ctx, host, loginOptions); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.PushChart(ctx, chartArchive, "oci://"+host+"/charts", &loginOptions.RegistryOptions); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.RegistryLogout(host); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := &ChartSpec{
+		ReleaseName: "oci",
+		ChartName:   "oci://" + host + "/charts/test-chart",
+		Namespace:   "default",
+		Version:     "0.1.0",
+		PlainHTTP:   true,
+		Username:    "user",
+		Password:    "secret",
+	}
+
+	if _, err := c.InstallChart(ctx, spec, nil); err != nil {
+		t.Fatal(err)
+	}
+}
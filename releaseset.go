@@ -0,0 +1,171 @@
+package helmclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	"sigs.k8s.io/yaml"
+)
+
+// ReleaseSetLabel is the release label identifying the release set a release was applied by.
+// It is used by Reconcile to find the releases to prune.
+const ReleaseSetLabel = "go-helm-client.mittwald.de/release-set"
+
+// LoadReleaseSet reads the release set from the YAML file at the provided path, see ParseReleaseSet.
+func LoadReleaseSet(path string) (*ReleaseSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read release set: %w", err)
+	}
+
+	return ParseReleaseSet(data)
+}
+
+// ParseReleaseSet parses a release set from the provided YAML document.
+// Unknown fields are rejected. In addition to nanoseconds, release timeouts may be given as duration strings, e.g. '5m'.
+func ParseReleaseSet(data []byte) (*ReleaseSet, error) {
+	document := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse release set: %w", err)
+	}
+
+	releases, _ := document["releases"].([]interface{})
+	for i, r := range releases {
+		r, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if timeout, ok := r["timeout"].(string); ok {
+			duration, err := time.ParseDuration(timeout)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse timeout of release %d: %w", i, err)
+			}
+			r["timeout"] = duration
+		}
+	}
+
+	normalized, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(normalized))
+	decoder.DisallowUnknownFields()
+
+	set := &ReleaseSet{}
+	if err := decoder.Decode(set); err != nil {
+		return nil, fmt.Errorf("failed to parse release set: %w", err)
+	}
+
+	if set.Name == "" {
+		return nil, ErrReleaseSetNameNotSet
+	}
+
+	return set, nil
+}
+
+// Reconcile adds or updates the repositories of the provided release set and installs or upgrades its releases
+// in the order of their dependencies, see ApplyReleases. The releases are labeled with the name of the set, see
+// ReleaseSetLabel. If pruning is enabled, labeled releases of the set that are no longer listed are uninstalled
+// afterward from the namespace of the client and the namespaces of the listed releases. Pruning is skipped if a repository or release failed, so a broken set does not tear down an environment.
+func (c *HelmClient) Reconcile(ctx context.Context, set *ReleaseSet, opts *ReconcileOptions) (*ReconcileResult, error) {
+	if opts == nil {
+		opts = &ReconcileOptions{}
+	}
+
+	if set.Name == "" {
+		return nil, ErrReleaseSetNameNotSet
+	}
+
+	result := &ReconcileResult{Repositories: make(map[string]ChartRepoChange, len(set.Repositories))}
+
+	for _, entry := range set.Repositories {
+		change, err := c.AddOrUpdateChartRepoWithOptions(ctx, entry, nil)
+		if err != nil {
+			return result, fmt.Errorf("failed to add repository %q: %w", entry.Name, err)
+		}
+
+		result.Repositories[entry.Name] = change
+	}
+
+	specs := make([]*ChartSpec, len(set.Releases))
+	for i := range set.Releases {
+		spec := set.Releases[i].DeepCopy()
+		if spec.Labels == nil {
+			spec.Labels = map[string]string{}
+		}
+		spec.Labels[ReleaseSetLabel] = set.Name
+
+		specs[i] = spec
+	}
+
+	applyResults, err := c.ApplyReleases(ctx, specs, &opts.ApplyReleasesOptions)
+	result.Releases = applyResults
+	if err != nil || !opts.Prune {
+		return result, err
+	}
+
+	result.Pruned, err = c.pruneReleaseSet(ctx, set, applyResults)
+
+	return result, err
+}
+
+// pruneReleaseSet uninstalls the releases labeled with the name of the provided set that were not applied.
+// The releases are listed in the namespace of the client and the namespaces of the applied releases.
+func (c *HelmClient) pruneReleaseSet(ctx context.Context, set *ReleaseSet, applied []*ReleaseApplyResult) ([]*release.Release, error) {
+	listed := make(map[string]bool, len(applied))
+	namespaces := make([]string, 0, len(applied))
+	for _, result := range applied {
+		if result.Release != nil {
+			listed[result.Namespace+"/"+result.Release.Name] = true
+		}
+		namespaces = append(namespaces, result.Namespace)
+	}
+
+	clients, err := c.namespaceClients(namespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prune the namespaces in a stable order.
+	namespaces = slices.Sorted(maps.Keys(clients))
+
+	var pruned []*release.Release
+	for _, namespace := range namespaces {
+		namespaced := clients[namespace]
+
+		listClient := action.NewList(namespaced.ActionConfig)
+		listClient.StateMask = action.ListAll
+		listClient.Selector = ReleaseSetLabel + "=" + set.Name
+
+		labeled, err := runWithContext(ctx, listClient.Run)
+		if err != nil {
+			return pruned, fmt.Errorf("failed to list releases of release set %q in namespace %q: %w", set.Name, namespace, err)
+		}
+
+		for _, rel := range labeled {
+			if listed[namespace+"/"+rel.Name] {
+				continue
+			}
+
+			c.DebugLog("pruning release %q in namespace %q of release set %q", rel.Name, namespace, set.Name)
+
+			if err := namespaced.uninstallReleaseByName(ctx, rel.Name); err != nil {
+				return pruned, fmt.Errorf("failed to prune release %q: %w", rel.Name, err)
+			}
+
+			pruned = append(pruned, rel)
+		}
+	}
+
+	return pruned, nil
+}
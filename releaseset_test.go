@@ -0,0 +1,231 @@
+package helmclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+)
+
+func TestParseReleaseSet(t *testing.T) {
+	set, err := ParseReleaseSet([]byte(`
+name: staging
+repositories:
+  - name: stable
+    url: https://charts.example.com
+releases:
+  - release: app
+    chart: stable/app
+    namespace: apps
+    timeout: 5m
+    dependsOn: [database]
+    valuesYaml: |
+      replicas: 2
+  - release: database
+    chart: stable/database
+    namespace: apps
+    timeout: 1000000000
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if set.Name != "staging" || len(set.Repositories) != 1 || set.Repositories[0].URL != "https://charts.example.com" {
+		t.Errorf("unexpected release set: %+v", set)
+	}
+
+	if len(set.Releases) != 2 {
+		t.Fatalf("expected 2 releases, got %d", len(set.Releases))
+	}
+
+	app := set.Releases[0]
+	if app.ReleaseName != "app" || app.Timeout != 5*time.Minute || app.DependsOn[0] != "database" || app.ValuesYaml != "replicas: 2\n" {
+		t.Errorf("unexpected release: %+v", app)
+	}
+
+	if set.Releases[1].Timeout != time.Second {
+		t.Errorf("expected a timeout of 1s, got %s", set.Releases[1].Timeout)
+	}
+
+	invalid := map[string]string{
+		"unknown field":    "name: staging\nreleases:\n  - release: app\n    charts: stable/app\n",
+		"invalid timeout":  "name: staging\nreleases:\n  - release: app\n    timeout: soon\n",
+		"missing name":     "releases:\n  - release: app\n",
+		"invalid document": "name: [",
+	}
+
+	for name, document := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseReleaseSet([]byte(document)); err == nil {
+				t.Error("expected the release set to be rejected")
+			}
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	var downloads atomic.Int32
+	server := newTestRepositoryServer(t, &downloads)
+
+	chartPath := writeTestChart(t, map[string]string{
+		"Chart.yaml":               "apiVersion: v2\nname: test-chart\nversion: 0.1.0\n",
+		"templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\n",
+	})
+
+	// A release that is not managed by the release set must never be pruned.
+	if _, err := c.InstallChart(ctx, &ChartSpec{ReleaseName: "unmanaged", ChartName: chartPath, Namespace: "default"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	set, err := ParseReleaseSet([]byte(fmt.Sprintf(`
+name: staging
+repositories:
+  - name: test
+    url: %s
+releases:
+  - release: app
+    chart: %s
+    namespace: default
+    dependsOn: [database]
+  - release: database
+    chart: %s
+    namespace: default
+`, server.URL, chartPath, chartPath)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := c.Reconcile(ctx, set, &ReconcileOptions{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Repositories["test"] != ChartRepoAdded {
+		t.Errorf("expected the repository to be added, got %q", result.Repositories["test"])
+	}
+
+	for _, applied := range result.Releases {
+		if applied.Err != nil {
+			t.Errorf("expected %q to be deployed: %v", applied.ReleaseName, applied.Err)
+			continue
+		}
+
+		if applied.Release.Labels[ReleaseSetLabel] != "staging" {
+			t.Errorf("expected %q to be labeled with the release set, got %v", applied.ReleaseName, applied.Release.Labels)
+		}
+	}
+
+	if set.Releases[0].Labels != nil {
+		t.Error("expected the release set not to be modified")
+	}
+
+	set.Releases = set.Releases[1:]
+
+	// Pruning is disabled, so the removed release is kept.
+	result, err = c.Reconcile(ctx, set, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Pruned) != 0 {
+		t.Errorf("expected no release to be pruned, got %d", len(result.Pruned))
+	}
+
+	result, err = c.Reconcile(ctx, set, &ReconcileOptions{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Pruned) != 1 || result.Pruned[0].Name != "app" {
+		t.Fatalf("expected 'app' to be pruned, got %v", result.Pruned)
+	}
+
+	if result.Repositories["test"] != ChartRepoUnchanged {
+		t.Errorf("expected the repository to be unchanged, got %q", result.Repositories["test"])
+	}
+
+	releases, err := c.ListReleasesByStateMaskWithContext(ctx, action.ListDeployed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, rel := range releases {
+		names = append(names, rel.Name)
+	}
+
+	if len(names) != 2 || names[0] != "database" || names[1] != "unmanaged" {
+		t.Errorf("expected the releases 'database' and 'unmanaged' to remain, got %v", names)
+	}
+
+	_, err = c.Reconcile(ctx, &ReleaseSet{}, nil)
+	if !errors.Is(err, ErrReleaseSetNameNotSet) {
+		t.Errorf("expected ErrReleaseSetNameNotSet, got %v", err)
+	}
+}
+
+func TestReconcileKeepsAppliedReleases(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	chartPath := writeTestChart(t, map[string]string{
+		"Chart.yaml":               "apiVersion: v2\nname: test-chart\nversion: 0.1.0\n",
+		"templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\n",
+	})
+
+	// Neither the release without a namespace nor the release with a generated name may be pruned after being applied.
+	set := &ReleaseSet{
+		Name: "staging",
+		Releases: []ChartSpec{
+			{ReleaseName: "app", ChartName: chartPath},
+			{GenerateName: true, NameTemplate: "worker-{{ randAlphaNum 8 | lower }}", ChartName: chartPath},
+		},
+	}
+
+	result, err := c.Reconcile(ctx, set, &ReconcileOptions{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Pruned) != 0 {
+		t.Errorf("expected no release to be pruned, got %v", result.Pruned)
+	}
+
+	releases, err := c.ListDeployedReleases()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(releases) != 2 {
+		t.Errorf("expected both releases to be deployed, got %d", len(releases))
+	}
+}
+
+func TestLoadReleaseSet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "releases.yaml")
+	if err := os.WriteFile(path, []byte("name: staging\nreleases:\n  - release: app\n    chart: stable/app\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	set, err := LoadReleaseSet(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(set.Releases) != 1 || set.Releases[0].ChartName != "stable/app" {
+		t.Errorf("unexpected release set: %+v", set)
+	}
+
+	if _, err := LoadReleaseSet(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected loading a missing file to fail")
+	}
+}
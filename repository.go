@@ -0,0 +1,319 @@
+package helmclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v3/pkg/helmpath"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// defaultChartRepoUpdateConcurrency is the number of repositories updated in parallel by default.
+const defaultChartRepoUpdateConcurrency = 8
+
+// repositoryStorage holds the chart repositories of a client, guarding concurrent access
+// to the repository file at 'path'.
+type repositoryStorage struct {
+	mu   sync.RWMutex
+	path string
+	file *repo.File
+	// updates serializes adding and updating entries including the download of their index,
+	// so that concurrent calls for the same entry compare it with the stored one only after the previous call persisted it,
+	// and indexes are not written to the same cache file concurrently.
+	updates chan struct{}
+}
+
+// loadRepositoryStorage loads the repository file at 'path'.
+// If the file does not exist yet, the storage starts without any repositories.
+func loadRepositoryStorage(path string) (*repositoryStorage, error) {
+	file, err := repo.LoadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to load repository config %q: %w", path, err)
+		}
+
+		file = repo.NewFile()
+	}
+
+	return &repositoryStorage{path: path, file: file, updates: make(chan struct{}, 1)}, nil
+}
+
+// Has returns true if a repository with the provided name exists.
+func (s *repositoryStorage) Has(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.file.Has(name)
+}
+
+// Get returns a copy of the repository entry with the provided name, or nil if it does not exist.
+func (s *repositoryStorage) Get(name string) *repo.Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry := s.file.Get(name)
+	if entry == nil {
+		return nil
+	}
+
+	e := *entry
+	return &e
+}
+
+// Entries returns copies of all repository entries.
+func (s *repositoryStorage) Entries() []*repo.Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]*repo.Entry, 0, len(s.file.Repositories))
+	for _, entry := range s.file.Repositories {
+		if entry == nil {
+			continue
+		}
+
+		e := *entry
+		entries = append(entries, &e)
+	}
+
+	return entries
+}
+
+// Update adds or replaces the provided entries and persists the repository file.
+func (s *repositoryStorage) Update(entries ...*repo.Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.file.Update(entries...)
+
+	return s.file.WriteFile(s.path, 0o644)
+}
+
+// Remove removes the entry with the provided name and persists the repository file.
+// It returns false if no such entry exists.
+func (s *repositoryStorage) Remove(name string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.file.Remove(name) {
+		return false, nil
+	}
+
+	return true, s.file.WriteFile(s.path, 0o644)
+}
+
+// Save persists the repository file.
+func (s *repositoryStorage) Save() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.file.WriteFile(s.path, 0o644)
+}
+
+// ListChartRepos returns the chart repositories stored in the client's repository config.
+func (c *HelmClient) ListChartRepos() []*repo.Entry {
+	return c.storage.Entries()
+}
+
+// RemoveChartRepo removes the chart repository with the provided name from the client's repository config
+// and deletes its cached index and charts files.
+func (c *HelmClient) RemoveChartRepo(name string) error {
+	removed, err := c.storage.Remove(name)
+	if err != nil {
+		return fmt.Errorf("failed to remove repository %q: %w", name, err)
+	}
+
+	if !removed {
+		return fmt.Errorf("%w: %q", ErrChartRepoNotFound, name)
+	}
+
+	for _, cacheFile := range []string{helmpath.CacheIndexFile(name), helmpath.CacheChartsFile(name)} {
+		err := os.Remove(filepath.Join(c.Settings.RepositoryCache, cacheFile))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove cache file of repository %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// AddOrUpdateChartRepo adds or updates the provided helm chart repository.
+func (c *HelmClient) AddOrUpdateChartRepo(entry repo.Entry) error {
+	return c.AddOrUpdateChartRepoWithContext(context.Background(), entry)
+}
+
+// AddOrUpdateChartRepoWithContext adds or updates the provided helm chart repository.
+// The index download is bound to the provided context.
+func (c *HelmClient) AddOrUpdateChartRepoWithContext(ctx context.Context, entry repo.Entry) error {
+	_, err := c.AddOrUpdateChartRepoWithOptions(ctx, entry, nil)
+	return err
+}
+
+// AddOrUpdateChartRepoWithOptions adds the provided helm chart repository or updates it if its entry differs
+// from the stored one, e.g. because of a changed URL or credentials. The index of added or updated repositories
+// is downloaded before the entry is persisted. The returned ChartRepoChange reports what happened to the entry.
+// Concurrent calls are serialized, so that only one of several calls adding the same entry reports it as added.
+func (c *HelmClient) AddOrUpdateChartRepoWithOptions(ctx context.Context, entry repo.Entry, opts *ChartRepoOptions) (_ ChartRepoChange, err error) {
+	defer c.observeOperation(OperationEvent{Operation: OperationRepoAdd, Repository: entry.Name}, time.Now(), &err)
+
+	if opts == nil {
+		opts = &ChartRepoOptions{}
+	}
+
+	select {
+	case c.storage.updates <- struct{}{}:
+		defer func() { <-c.storage.updates }()
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+
+	var change ChartRepoChange
+	switch existing := c.storage.Get(entry.Name); {
+	case existing == nil:
+		change = ChartRepoAdded
+	case *existing != entry:
+		change = ChartRepoUpdated
+	case opts.ForceUpdate:
+		// The entry is unchanged, but its index is refreshed nonetheless.
+		change = ChartRepoUnchanged
+	default:
+		c.DebugLog("repository %q is unchanged", entry.Name)
+		return ChartRepoUnchanged, nil
+	}
+
+	chartRepo, err := repo.NewChartRepository(&entry, contextProviders(ctx, c.Providers))
+	if err != nil {
+		return "", err
+	}
+
+	chartRepo.CachePath = c.Settings.RepositoryCache
+
+	if !registry.IsOCI(entry.URL) {
+		_, err = chartRepo.DownloadIndexFile()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if err := c.storage.Update(&entry); err != nil {
+		return "", err
+	}
+
+	c.DebugLog("repository %q %s", entry.Name, change)
+
+	return change, nil
+}
+
+// UpdateChartRepos updates the list of chart repositories stored in the client's cache.
+func (c *HelmClient) UpdateChartRepos() error {
+	return c.UpdateChartReposWithContext(context.Background())
+}
+
+// UpdateChartReposWithContext updates the list of chart repositories stored in the client's cache.
+// The index downloads are bound to the provided context.
+func (c *HelmClient) UpdateChartReposWithContext(ctx context.Context) error {
+	_, err := c.UpdateChartReposWithOptions(ctx, nil)
+	return err
+}
+
+// UpdateChartReposWithOptions downloads the indexes of all chart repositories stored in the client's cache
+// in parallel. Failing repositories do not prevent the others from being updated. The returned results are
+// in the order of the repository file, and the returned error joins the errors of all failed repositories.
+// Updates are serialized with AddOrUpdateChartRepoWithOptions, so that both do not write the index of a repository concurrently.
+func (c *HelmClient) UpdateChartReposWithOptions(ctx context.Context, opts *UpdateChartReposOptions) ([]*ChartRepoUpdateResult, error) {
+	concurrency := defaultChartRepoUpdateConcurrency
+	if opts != nil && opts.Concurrency > 0 {
+		concurrency = opts.Concurrency
+	}
+
+	select {
+	case c.storage.updates <- struct{}{}:
+		defer func() { <-c.storage.updates }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	entries := c.storage.Entries()
+	results := make([]*ChartRepoUpdateResult, len(entries))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+
+	for i, entry := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				results[i] = &ChartRepoUpdateResult{Name: entry.Name, URL: entry.URL, Err: ctx.Err()}
+				return
+			}
+
+			results[i] = c.updateChartRepo(ctx, entry)
+		}()
+	}
+
+	wg.Wait()
+
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			c.DebugLog("failed to update repository %q: %s", result.Name, result.Err)
+			errs = append(errs, fmt.Errorf("failed to update repository %q: %w", result.Name, result.Err))
+		}
+	}
+
+	if err := c.storage.Save(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// updateChartRepo downloads the index of the provided chart repository.
+func (c *HelmClient) updateChartRepo(ctx context.Context, entry *repo.Entry) *ChartRepoUpdateResult {
+	result := &ChartRepoUpdateResult{Name: entry.Name, URL: entry.URL}
+
+	start := time.Now()
+	defer func() {
+		result.Duration = time.Since(start)
+	}()
+	defer c.observeOperation(OperationEvent{Operation: OperationRepoUpdate, Repository: entry.Name}, start, &result.Err)
+
+	if registry.IsOCI(entry.URL) {
+		return result
+	}
+
+	chartRepo, err := repo.NewChartRepository(entry, contextProviders(ctx, c.Providers))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	chartRepo.CachePath = c.Settings.RepositoryCache
+
+	indexPath, err := chartRepo.DownloadIndexFile()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	index, err := repo.LoadIndexFile(indexPath)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Entries = len(index.Entries)
+
+	return result
+}
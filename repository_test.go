@@ -0,0 +1,399 @@
+package helmclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/helmpath"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+func TestLoadRepositoryStorage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repositories.yaml")
+
+	storage, err := loadRepositoryStorage(path)
+	if err != nil {
+		t.Fatalf("expected a missing repository file to be accepted, got %v", err)
+	}
+
+	if len(storage.Entries()) != 0 {
+		t.Fatalf("expected no repositories, got %v", storage.Entries())
+	}
+
+	if err := storage.Update(&repo.Entry{Name: "stable", URL: "https://charts.helm.sh/stable"}); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := loadRepositoryStorage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reloaded.Has("stable") {
+		t.Errorf("expected previously added repositories to be loaded, got %v", reloaded.Entries())
+	}
+
+	other, err := loadRepositoryStorage(filepath.Join(t.TempDir(), "repositories.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if other.Has("stable") {
+		t.Errorf("expected storages of different repository files to be independent")
+	}
+}
+
+func TestLoadRepositoryStorageInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repositories.yaml")
+	if err := os.WriteFile(path, []byte("repositories: {"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := loadRepositoryStorage(path); err == nil {
+		t.Errorf("expected an invalid repository file to be rejected")
+	}
+}
+
+func TestAddOrUpdateChartRepoConcurrently(t *testing.T) {
+	c := newTestClient(t)
+
+	const count = 20
+
+	var wg sync.WaitGroup
+	errs := make(chan error, count)
+
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			// OCI repositories do not have an index, so no download takes place.
+			errs <- c.AddOrUpdateChartRepo(repo.Entry{
+				Name: fmt.Sprintf("repo-%d", i),
+				URL:  fmt.Sprintf("oci://registry.example.com/charts-%d", i),
+			})
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reloaded, err := loadRepositoryStorage(c.Settings.RepositoryConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reloaded.Entries()) != count {
+		t.Errorf("expected %d persisted repositories, got %d", count, len(reloaded.Entries()))
+	}
+}
+
+// newTestRepositoryServer returns a chart repository serving an empty index, counting the index downloads.
+func newTestRepositoryServer(t *testing.T, downloads *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/index.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+
+		downloads.Add(1)
+		_, _ = w.Write([]byte("apiVersion: v1\nentries: {}\n"))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestAddOrUpdateChartRepoWithOptions(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	var downloads atomic.Int32
+	server := newTestRepositoryServer(t, &downloads)
+
+	entry := repo.Entry{Name: "test", URL: server.URL}
+
+	steps := []struct {
+		name              string
+		entry             repo.Entry
+		opts              *ChartRepoOptions
+		expectedChange    ChartRepoChange
+		expectedDownloads int32
+	}{
+		{name: "add", entry: entry, expectedChange: ChartRepoAdded, expectedDownloads: 1},
+		{name: "unchanged", entry: entry, expectedChange: ChartRepoUnchanged, expectedDownloads: 1},
+		{name: "forced", entry: entry, opts: &ChartRepoOptions{ForceUpdate: true}, expectedChange: ChartRepoUnchanged, expectedDownloads: 2},
+		{name: "credentials changed", entry: repo.Entry{Name: "test", URL: server.URL, Username: "foo", Password: "bar"}, expectedChange: ChartRepoUpdated, expectedDownloads: 3},
+	}
+
+	for _, step := range steps {
+		change, err := c.AddOrUpdateChartRepoWithOptions(ctx, step.entry, step.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		if change != step.expectedChange {
+			t.Errorf("%s: expected change %q, got %q", step.name, step.expectedChange, change)
+		}
+
+		if downloads.Load() != step.expectedDownloads {
+			t.Errorf("%s: expected %d index downloads, got %d", step.name, step.expectedDownloads, downloads.Load())
+		}
+	}
+
+	reloaded, err := loadRepositoryStorage(c.Settings.RepositoryConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if persisted := reloaded.Get("test"); persisted == nil || persisted.Username != "foo" {
+		t.Errorf("expected the updated entry to be persisted, got %v", persisted)
+	}
+}
+
+func TestAddOrUpdateChartRepoWithOptionsConcurrently(t *testing.T) {
+	c := newTestClient(t)
+
+	var downloads atomic.Int32
+	server := newTestRepositoryServer(t, &downloads)
+
+	const count = 10
+
+	var wg sync.WaitGroup
+	changes := make(chan ChartRepoChange, count)
+
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			change, err := c.AddOrUpdateChartRepoWithOptions(context.Background(), repo.Entry{Name: "test", URL: server.URL}, nil)
+			if err != nil {
+				t.Error(err)
+			}
+			changes <- change
+		}()
+	}
+
+	wg.Wait()
+	close(changes)
+
+	added := 0
+	for change := range changes {
+		if change == ChartRepoAdded {
+			added++
+		}
+	}
+
+	if added != 1 || downloads.Load() != 1 {
+		t.Errorf("expected the repository to be added and downloaded once, got %d additions and %d downloads", added, downloads.Load())
+	}
+}
+
+func TestUpdateChartReposWithOptions(t *testing.T) {
+	c := newTestClient(t)
+
+	var downloads atomic.Int32
+	server := newTestRepositoryServer(t, &downloads)
+
+	err := c.storage.Update(
+		&repo.Entry{Name: "good", URL: server.URL},
+		&repo.Entry{Name: "broken", URL: server.URL + "/broken"},
+		&repo.Entry{Name: "oci", URL: "oci://registry.example.com/charts"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := c.UpdateChartReposWithOptions(context.Background(), &UpdateChartReposOptions{Concurrency: 2})
+	if err == nil {
+		t.Fatal("expected the failing repository to be reported")
+	}
+
+	if !strings.Contains(err.Error(), `"broken"`) || strings.Contains(err.Error(), `"good"`) {
+		t.Errorf("expected only the failing repository in the error, got %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected a result per repository, got %d", len(results))
+	}
+
+	for i, name := range []string{"good", "broken", "oci"} {
+		if results[i].Name != name {
+			t.Errorf("expected result %d to belong to %q, got %q", i, name, results[i].Name)
+		}
+	}
+
+	if results[0].Err != nil {
+		t.Errorf("expected the reachable repository to be updated, got %v", results[0].Err)
+	}
+
+	if results[1].Err == nil {
+		t.Error("expected the unreachable repository to fail")
+	}
+
+	if results[2].Err != nil {
+		t.Errorf("expected OCI repositories to be skipped, got %v", results[2].Err)
+	}
+
+	if downloads.Load() != 1 {
+		t.Errorf("expected 1 index download, got %d", downloads.Load())
+	}
+}
+
+func TestRemoveChartRepo(t *testing.T) {
+	c := newTestClient(t)
+
+	var downloads atomic.Int32
+	server := newTestRepositoryServer(t, &downloads)
+
+	for _, name := range []string{"first", "second"} {
+		if err := c.AddOrUpdateChartRepo(repo.Entry{Name: name, URL: server.URL}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if repos := c.ListChartRepos(); len(repos) != 2 {
+		t.Fatalf("expected 2 repositories, got %d", len(repos))
+	}
+
+	indexFile := filepath.Join(c.Settings.RepositoryCache, helmpath.CacheIndexFile("first"))
+	if _, err := os.Stat(indexFile); err != nil {
+		t.Fatalf("expected the index to be cached: %v", err)
+	}
+
+	if err := c.RemoveChartRepo("first"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(indexFile); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected the cached index to be removed, got %v", err)
+	}
+
+	repos := c.ListChartRepos()
+	if len(repos) != 1 || repos[0].Name != "second" {
+		t.Errorf("expected only the second repository to remain, got %v", repos)
+	}
+
+	reloaded, err := loadRepositoryStorage(c.Settings.RepositoryConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reloaded.Has("first") {
+		t.Error("expected the removal to be persisted")
+	}
+
+	if err := c.RemoveChartRepo("first"); !errors.Is(err, ErrChartRepoNotFound) {
+		t.Errorf("expected ErrChartRepoNotFound, got %v", err)
+	}
+}
+
+func TestInstallChartFromRepoURL(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	chartPath := writeTestChart(t, map[string]string{
+		"Chart.yaml":               "apiVersion: v2\nname: test-chart\nversion: 0.1.0\n",
+		"templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\n",
+	})
+
+	helmChart, err := loader.LoadDir(chartPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chartArchive, err := chartutil.Save(helmChart, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/index.yaml":
+			_, _ = fmt.Fprintf(w, "apiVersion: v1\nentries:\n  test-chart:\n  - name: test-chart\n    version: 0.1.0\n    urls: [%s/test-chart-0.1.0.tgz]\n", server.URL)
+		case "/test-chart-0.1.0.tgz":
+			http.ServeFile(w, r, chartArchive)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	spec := &ChartSpec{ReleaseName: "private", ChartName: "test-chart", Namespace: "default", RepoURL: server.URL}
+	if _, err := c.InstallChart(ctx, spec, nil); err == nil {
+		t.Error("expected the installation without credentials to fail")
+	}
+
+	spec.Username = "user"
+	spec.Password = "secret"
+	if _, err := c.InstallChart(ctx, spec, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(c.Settings.RepositoryConfig); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected the repository config to be left untouched, got %v", err)
+	}
+}
+
+func TestInstallChartFromRepoURLCancelled(t *testing.T) {
+	c := newTestClient(t)
+
+	downloadCancelled := make(chan struct{})
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.yaml":
+			_, _ = fmt.Fprintf(w, "apiVersion: v1\nentries:\n  test-chart:\n  - name: test-chart\n    version: 0.1.0\n    urls: [%s/test-chart-0.1.0.tgz]\n", server.URL)
+		case "/test-chart-0.1.0.tgz":
+			// Hold back the chart until the client gives up on the download.
+			<-r.Context().Done()
+			close(downloadCancelled)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	spec := &ChartSpec{ReleaseName: "app", ChartName: "test-chart", Namespace: "default", RepoURL: server.URL}
+	if _, err := c.InstallChart(ctx, spec, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the installation to exceed its deadline, got %v", err)
+	}
+
+	select {
+	case <-downloadCancelled:
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected the chart download to be cancelled with the context")
+	}
+}
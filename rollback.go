@@ -0,0 +1,137 @@
+package helmclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// RollbackToRevision rolls back the release with the provided name to the provided revision
+// and returns the release created by the rollback. A revision of 0 rolls back to the previous revision.
+// The deadline of the provided context bounds the configured timeout of the rollback.
+func (c *HelmClient) RollbackToRevision(ctx context.Context, name string, revision int, opts *RollbackOptions) (_ *release.Release, err error) {
+	defer c.observeOperation(OperationEvent{Operation: OperationRollback, ReleaseName: name, Namespace: c.Settings.Namespace()}, time.Now(), &err)
+
+	if opts == nil {
+		opts = &RollbackOptions{}
+	}
+
+	client := action.NewRollback(c.ActionConfig)
+	client.Version = revision
+
+	mergeRollbackToRevisionOptions(opts, client)
+	client.Timeout = timeoutWithContext(ctx, client.Timeout)
+
+	_, err = runWithContext(ctx, func() (struct{}, error) {
+		return struct{}{}, client.Run(name)
+	})
+	if err != nil {
+		return nil, releaseError(name, err)
+	}
+
+	rel, err := c.ActionConfig.Releases.Last(name)
+	if err != nil {
+		return nil, releaseError(name, err)
+	}
+
+	c.DebugLog("release rolled back to revision %d: %s/%s", revision, rel.Name, rel.Namespace)
+
+	return rel, nil
+}
+
+// LastSuccessfulRevision returns the latest revision of the release with the provided name that was deployed successfully,
+// i.e. whose status is deployed or superseded. Failed, pending and uninstalled revisions are skipped.
+func (c *HelmClient) LastSuccessfulRevision(ctx context.Context, name string) (int, error) {
+	history, err := c.ListReleaseHistoryWithContext(ctx, name, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	revision := 0
+	for _, rel := range history {
+		switch rel.Info.Status {
+		case release.StatusDeployed, release.StatusSuperseded:
+			revision = max(revision, rel.Version)
+		}
+	}
+
+	if revision == 0 {
+		return 0, fmt.Errorf("%w: %q", ErrNoSuccessfulRevision, name)
+	}
+
+	return revision, nil
+}
+
+// RollbackFailedRelease implements FailedReleaseRollBack by rolling back the release of the provided spec
+// to its last successfully deployed revision, using the rollback settings of the spec.
+// Nothing is rolled back if the upgrade failed before creating a revision, as the release is unchanged then,
+// or if the spec is atomic, as helm rolled back the release already.
+func (c *HelmClient) RollbackFailedRelease(ctx context.Context, spec *ChartSpec, failed *release.Release, upgradeErr error) error {
+	if failed == nil || spec.Atomic {
+		return nil
+	}
+
+	revision, err := c.LastSuccessfulRevision(ctx, spec.ReleaseName)
+	if err != nil {
+		return err
+	}
+
+	c.DebugLog("rolling back revision %d of release %q to revision %d after: %s", failed.Version, spec.ReleaseName, revision, upgradeErr)
+
+	_, err = c.RollbackToRevision(ctx, spec.ReleaseName, revision, rollbackOptionsFromSpec(spec))
+
+	return err
+}
+
+// rollbackFailedUpgrade rolls back a failed upgrade using the rollback strategy of the provided options.
+func rollbackFailedUpgrade(ctx context.Context, spec *ChartSpec, opts *GenericHelmOptions, failed *release.Release, upgradeErr error) (RollbackOutcome, error) {
+	var err error
+
+	switch {
+	case opts == nil:
+		return RollbackNotAttempted, nil
+	case opts.FailedReleaseRollBack != nil:
+		err = opts.FailedReleaseRollBack.RollbackFailedRelease(ctx, spec, failed, upgradeErr)
+	case opts.RollBack != nil && failed == nil:
+		err = opts.RollBack.RollbackRelease(spec)
+	default:
+		return RollbackNotAttempted, nil
+	}
+
+	if err != nil {
+		return RollbackFailed, err
+	}
+
+	return RollbackSucceeded, nil
+}
+
+// rollbackOptionsFromSpec returns the rollback settings of the provided spec.
+func rollbackOptionsFromSpec(spec *ChartSpec) *RollbackOptions {
+	return &RollbackOptions{
+		DisableHooks:  spec.DisableHooks,
+		DryRun:        spec.DryRun,
+		Timeout:       spec.Timeout,
+		CleanupOnFail: spec.CleanupOnFail,
+		Force:         spec.Force,
+		MaxHistory:    spec.MaxHistory,
+		Recreate:      spec.Recreate,
+		Wait:          spec.Wait,
+		WaitForJobs:   spec.WaitForJobs,
+	}
+}
+
+// mergeRollbackToRevisionOptions merges the provided rollback options to helm rollback options used by the client.
+func mergeRollbackToRevisionOptions(opts *RollbackOptions, rollbackOptions *action.Rollback) {
+	rollbackOptions.DisableHooks = opts.DisableHooks
+	rollbackOptions.DryRun = opts.DryRun
+	rollbackOptions.Timeout = opts.Timeout
+	rollbackOptions.CleanupOnFail = opts.CleanupOnFail
+	rollbackOptions.Force = opts.Force
+	rollbackOptions.MaxHistory = opts.MaxHistory
+	rollbackOptions.Recreate = opts.Recreate
+	rollbackOptions.Wait = opts.Wait
+	rollbackOptions.WaitForJobs = opts.WaitForJobs
+}
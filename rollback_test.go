@@ -0,0 +1,117 @@
+package helmclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+func TestLastSuccessfulRevision(t *testing.T) {
+	ctx := context.Background()
+
+	c := newTestClient(t)
+	createTestRevision(t, c, 1, release.StatusSuperseded, 3*time.Hour)
+	createTestRevision(t, c, 2, release.StatusDeployed, 2*time.Hour)
+	createTestRevision(t, c, 3, release.StatusFailed, time.Hour)
+
+	revision, err := c.LastSuccessfulRevision(ctx, "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if revision != 2 {
+		t.Errorf("expected revision 2, got %d", revision)
+	}
+
+	c = newTestClient(t)
+	createTestRevision(t, c, 1, release.StatusFailed, time.Hour)
+
+	if _, err := c.LastSuccessfulRevision(ctx, "app"); !errors.Is(err, ErrNoSuccessfulRevision) {
+		t.Errorf("expected ErrNoSuccessfulRevision, got %v", err)
+	}
+
+	if _, err := c.LastSuccessfulRevision(ctx, "missing"); !errors.Is(err, ErrReleaseNotFound) {
+		t.Errorf("expected ErrReleaseNotFound, got %v", err)
+	}
+}
+
+func TestRollbackToRevision(t *testing.T) {
+	c := newTestClient(t)
+	createTestRevision(t, c, 1, release.StatusSuperseded, 3*time.Hour)
+	createTestRevision(t, c, 2, release.StatusSuperseded, 2*time.Hour)
+	createTestRevision(t, c, 3, release.StatusDeployed, time.Hour)
+
+	rel, err := c.RollbackToRevision(context.Background(), "app", 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rel.Version != 4 || rel.Info.Status != release.StatusDeployed || rel.Info.Description != "Rollback to 1" {
+		t.Errorf("expected revision 4 rolling back to revision 1, got revision %d %s: %s", rel.Version, rel.Info.Status, rel.Info.Description)
+	}
+
+	if _, err := c.RollbackToRevision(context.Background(), "missing", 1, nil); !errors.Is(err, ErrReleaseNotFound) {
+		t.Errorf("expected ErrReleaseNotFound, got %v", err)
+	}
+}
+
+func TestUpgradeChartFailedReleaseRollBack(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+	kubeClient := c.ActionConfig.KubeClient
+
+	spec := &ChartSpec{
+		ReleaseName: "app",
+		Namespace:   "default",
+		ChartName: writeTestChart(t, map[string]string{
+			"Chart.yaml":               "apiVersion: v2\nname: app\nversion: 0.1.0\n",
+			"templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n",
+		}),
+	}
+
+	if _, err := c.InstallChart(ctx, spec, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	updateErr := errors.New("update failed")
+	c.ActionConfig.KubeClient = &kubefake.FailingKubeClient{PrintingKubeClient: kubefake.PrintingKubeClient{Out: io.Discard}, UpdateError: updateErr}
+
+	var failed *release.Release
+	opts := &GenericHelmOptions{
+		FailedReleaseRollBack: FailedReleaseRollBackFunc(func(ctx context.Context, spec *ChartSpec, rel *release.Release, err error) error {
+			if !errors.Is(err, updateErr) {
+				t.Errorf("expected the upgrade error, got %v", err)
+			}
+
+			failed = rel
+			c.ActionConfig.KubeClient = kubeClient
+
+			return c.RollbackFailedRelease(ctx, spec, rel, err)
+		}),
+	}
+
+	_, err := c.UpgradeChart(ctx, spec, opts)
+
+	var upgradeErr *UpgradeError
+	if !errors.As(err, &upgradeErr) || upgradeErr.Rollback != RollbackSucceeded {
+		t.Fatalf("expected the failed upgrade to be rolled back, got %v", err)
+	}
+
+	if failed == nil || failed.Version != 2 || failed.Info.Status != release.StatusFailed {
+		t.Fatalf("expected the failed revision 2 to be passed, got %+v", failed)
+	}
+
+	rel, err := c.ActionConfig.Releases.Last("app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rel.Version != 3 || rel.Info.Status != release.StatusDeployed || rel.Info.Description != "Rollback to 1" {
+		t.Errorf("expected revision 3 rolling back to revision 1, got revision %d %s: %s", rel.Version, rel.Info.Status, rel.Info.Description)
+	}
+}
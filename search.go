@@ -0,0 +1,142 @@
+package helmclient
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"helm.sh/helm/v3/pkg/helmpath"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// SearchCharts searches the cached indexes of the client's chart repositories, similar to 'helm search repo'.
+// The indexes are downloaded by AddOrUpdateChartRepo and UpdateChartRepos; repositories without a cached
+// index, e.g. OCI repositories, are skipped. Unless AllVersions is set, only the latest matching version
+// of each chart is returned.
+func (c *HelmClient) SearchCharts(opts *SearchChartsOptions) ([]*ChartSearchResult, error) {
+	if opts == nil {
+		opts = &SearchChartsOptions{}
+	}
+
+	var nameRegexp *regexp.Regexp
+	if opts.Regexp != "" {
+		var err error
+		nameRegexp, err = regexp.Compile(opts.Regexp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chart name regexp %q: %w", opts.Regexp, err)
+		}
+	}
+
+	constraint, err := searchConstraint(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	keyword := strings.ToLower(opts.Keyword)
+
+	var results []*ChartSearchResult
+	for _, entry := range c.storage.Entries() {
+		indexPath := filepath.Join(c.Settings.RepositoryCache, helmpath.CacheIndexFile(entry.Name))
+
+		index, err := repo.LoadIndexFile(indexPath)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				c.DebugLog("repository %q has no cached index, skipping", entry.Name)
+			} else {
+				c.DebugLog("failed to load index of repository %q, skipping: %s", entry.Name, err)
+			}
+			continue
+		}
+
+		for chartName, versions := range index.Entries {
+			name := entry.Name + "/" + chartName
+			if nameRegexp != nil && !nameRegexp.MatchString(name) {
+				continue
+			}
+
+			// The versions are sorted from newest to oldest by repo.LoadIndexFile.
+			for _, version := range versions {
+				if !matchesKeyword(name, version, keyword) || !matchesConstraint(version, constraint, opts.Devel) {
+					continue
+				}
+
+				results = append(results, &ChartSearchResult{Name: name, Repository: entry.Name, Chart: version})
+				if !opts.AllVersions {
+					break
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Name != results[j].Name {
+			return results[i].Name < results[j].Name
+		}
+
+		return compareChartVersions(results[i].Chart, results[j].Chart) > 0
+	})
+
+	return results, nil
+}
+
+// searchConstraint returns the semver constraint the searched chart versions must satisfy, or nil if no Version is set.
+func searchConstraint(opts *SearchChartsOptions) (*semver.Constraints, error) {
+	if opts.Version == "" {
+		return nil, nil
+	}
+
+	constraint, err := semver.NewConstraint(opts.Version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version constraint %q: %w", opts.Version, err)
+	}
+
+	return constraint, nil
+}
+
+// matchesKeyword returns true if the keyword is contained in the chart's name, description or keywords.
+func matchesKeyword(name string, version *repo.ChartVersion, keyword string) bool {
+	if keyword == "" {
+		return true
+	}
+
+	fields := append([]string{name, version.Description}, version.Keywords...)
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), keyword) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesConstraint returns true if the chart version satisfies the constraint.
+// Without a constraint, development versions only match if devel is set.
+// Charts with versions that are not valid semver never match a constraint.
+func matchesConstraint(version *repo.ChartVersion, constraint *semver.Constraints, devel bool) bool {
+	v, err := semver.NewVersion(version.Version)
+	if constraint == nil {
+		return devel || err != nil || v.Prerelease() == ""
+	}
+
+	if err != nil {
+		return false
+	}
+
+	return constraint.Check(v)
+}
+
+// compareChartVersions compares the semver versions of two charts, falling back to a string comparison.
+func compareChartVersions(a, b *repo.ChartVersion) int {
+	va, errA := semver.NewVersion(a.Version)
+	vb, errB := semver.NewVersion(b.Version)
+	if errA != nil || errB != nil {
+		return strings.Compare(a.Version, b.Version)
+	}
+
+	return va.Compare(vb)
+}
@@ -0,0 +1,93 @@
+package helmclient
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/helmpath"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+const testSearchIndex = `apiVersion: v1
+entries:
+  nginx:
+  - name: nginx
+    version: 2.0.0-rc.1
+    description: A web server
+  - name: nginx
+    version: 1.2.0
+    description: A web server
+    keywords: [http]
+  - name: nginx
+    version: 1.1.0
+    description: A web server
+  redis:
+  - name: redis
+    version: 3.0.0
+    description: An in-memory database
+  scratch:
+  - name: scratch
+    version: 0.0.0
+    description: An unreleased chart
+`
+
+func TestSearchCharts(t *testing.T) {
+	c := newTestClient(t)
+
+	if err := c.storage.Update(&repo.Entry{Name: "stable", URL: "https://example.com"}, &repo.Entry{Name: "uncached", URL: "https://example.org"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(c.Settings.RepositoryCache, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	indexPath := filepath.Join(c.Settings.RepositoryCache, helmpath.CacheIndexFile("stable"))
+	if err := os.WriteFile(indexPath, []byte(testSearchIndex), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     *SearchChartsOptions
+		expected []string
+	}{
+		{name: "latest", opts: nil, expected: []string{"stable/nginx@1.2.0", "stable/redis@3.0.0", "stable/scratch@0.0.0"}},
+		{name: "devel", opts: &SearchChartsOptions{Devel: true}, expected: []string{"stable/nginx@2.0.0-rc.1", "stable/redis@3.0.0", "stable/scratch@0.0.0"}},
+		{name: "keyword", opts: &SearchChartsOptions{Keyword: "DATABASE"}, expected: []string{"stable/redis@3.0.0"}},
+		{name: "keywords", opts: &SearchChartsOptions{Keyword: "http", AllVersions: true}, expected: []string{"stable/nginx@1.2.0"}},
+		{name: "regexp", opts: &SearchChartsOptions{Regexp: "^stable/ngi"}, expected: []string{"stable/nginx@1.2.0"}},
+		{name: "constraint", opts: &SearchChartsOptions{Version: "~1.1.0"}, expected: []string{"stable/nginx@1.1.0"}},
+		{name: "all versions", opts: &SearchChartsOptions{Regexp: "nginx", AllVersions: true}, expected: []string{"stable/nginx@1.2.0", "stable/nginx@1.1.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := c.SearchCharts(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := make([]string, 0, len(results))
+			for _, result := range results {
+				actual = append(actual, result.Name+"@"+result.Chart.Version)
+			}
+
+			if len(actual) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, actual)
+			}
+
+			for i := range actual {
+				if actual[i] != tt.expected[i] {
+					t.Errorf("expected %v, got %v", tt.expected, actual)
+					break
+				}
+			}
+		})
+	}
+
+	if _, err := c.SearchCharts(&SearchChartsOptions{Regexp: "("}); err == nil {
+		t.Error("expected an invalid regexp to be rejected")
+	}
+}
@@ -2,8 +2,11 @@ package helmclient
 
 import (
 	"fmt"
+	"slices"
 
+	"github.com/Masterminds/semver/v3"
 	"helm.sh/helm/v3/pkg/getter"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/yaml"
 
 	"github.com/mittwald/go-helm-client/values"
@@ -26,3 +29,78 @@ func (spec *ChartSpec) GetValuesMap(p getter.Providers) (map[string]interface{},
 
 	return values.MergeMaps(valuesYaml, valuesOptions), nil
 }
+
+// keyring returns the keyring used to verify the chart, falling back to the default GnuPG keyring if verification is enabled.
+func (spec *ChartSpec) keyring() string {
+	if spec.Keyring == "" && spec.Verify {
+		return defaultKeyring()
+	}
+
+	return spec.Keyring
+}
+
+// hasRegistrySettings returns true if the spec configures how to connect to the chart's OCI registry.
+func (spec *ChartSpec) hasRegistrySettings() bool {
+	return spec.Username != "" || spec.Password != "" || spec.CAFile != "" || spec.CertFile != "" ||
+		spec.KeyFile != "" || spec.InsecureSkipTLSVerify || spec.PlainHTTP
+}
+
+// validDeletionPropagations are the supported values of ChartSpec.DeletionPropagation.
+var validDeletionPropagations = []string{"orphan", "foreground", "background"}
+
+// validDryRunOptions are the supported values of ChartSpec.DryRunOption.
+var validDryRunOptions = []string{"none", "client", "server", "true", "false"}
+
+// Validate checks the spec for invalid or contradictory settings without contacting the cluster.
+// The paths of the returned errors are relative to the spec and use its JSON field names.
+// Installs and upgrades only validate the spec if enabled via GenericHelmOptions.ValidateSpec.
+func (spec *ChartSpec) Validate() field.ErrorList {
+	return spec.ValidateWithPath(nil)
+}
+
+// ValidateWithPath validates the spec like Validate, prefixing the paths of the returned errors with the provided path,
+// e.g. to report errors of a spec embedded in a custom resource from an admission webhook.
+func (spec *ChartSpec) ValidateWithPath(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if spec.ResetValues && spec.ReuseValues {
+		errs = append(errs, field.Invalid(path.Child("reuseValues"), spec.ReuseValues, "may not be set together with resetValues"))
+	}
+
+	if spec.GenerateName && spec.ReleaseName != "" {
+		errs = append(errs, field.Invalid(path.Child("release"), spec.ReleaseName, "must be empty if generateName is set"))
+	}
+
+	if spec.NameTemplate != "" && !spec.GenerateName {
+		errs = append(errs, field.Invalid(path.Child("nameTemplate"), spec.NameTemplate, "requires generateName to be set"))
+	}
+
+	if spec.WaitForJobs && !spec.Wait {
+		errs = append(errs, field.Invalid(path.Child("waitForJobs"), spec.WaitForJobs, "requires wait to be set"))
+	}
+
+	if spec.DeletionPropagation != "" && !slices.Contains(validDeletionPropagations, spec.DeletionPropagation) {
+		errs = append(errs, field.NotSupported(path.Child("deletionPropagation"), spec.DeletionPropagation, validDeletionPropagations))
+	}
+
+	if spec.DryRunOption != "" && !slices.Contains(validDryRunOptions, spec.DryRunOption) {
+		errs = append(errs, field.NotSupported(path.Child("dryRunOption"), spec.DryRunOption, validDryRunOptions))
+	}
+
+	if spec.MaxHistory < 0 {
+		errs = append(errs, field.Invalid(path.Child("maxHistory"), spec.MaxHistory, "must be greater than or equal to 0"))
+	}
+
+	if spec.Version != "" {
+		if _, err := semver.NewConstraint(spec.Version); err != nil {
+			errs = append(errs, field.Invalid(path.Child("version"), spec.Version, fmt.Sprintf("must be a semantic version or constraint: %v", err)))
+		}
+	}
+
+	if err := yaml.Unmarshal([]byte(spec.ValuesYaml), &map[string]interface{}{}); err != nil {
+		// The values are omitted, since they may contain secrets.
+		errs = append(errs, field.Invalid(path.Child("valuesYaml"), field.OmitValueType{}, err.Error()))
+	}
+
+	return errs
+}
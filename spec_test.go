@@ -0,0 +1,116 @@
+package helmclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestChartSpecValidate(t *testing.T) {
+	tests := map[string]struct {
+		spec     ChartSpec
+		expected []string
+	}{
+		"valid": {
+			spec: ChartSpec{
+				ReleaseName:         "app",
+				Version:             "~1.2.0",
+				Wait:                true,
+				WaitForJobs:         true,
+				MaxHistory:          10,
+				DryRunOption:        "server",
+				DeletionPropagation: "foreground",
+				ValuesYaml:          "replicas: 2\n",
+			},
+		},
+		"generated name": {
+			spec: ChartSpec{GenerateName: true, NameTemplate: "app-{{randAlpha 5}}"},
+		},
+		"contradictory settings": {
+			spec: ChartSpec{
+				ReleaseName:  "app",
+				GenerateName: true,
+				ResetValues:  true,
+				ReuseValues:  true,
+				WaitForJobs:  true,
+			},
+			expected: []string{"release", "reuseValues", "waitForJobs"},
+		},
+		"invalid values": {
+			spec: ChartSpec{
+				ReleaseName:         "app",
+				NameTemplate:        "app-{{randAlpha 5}}",
+				DeletionPropagation: "later",
+				DryRunOption:        "maybe",
+				MaxHistory:          -1,
+				Version:             "latest",
+				ValuesYaml:          "password: [secret",
+			},
+			expected: []string{"deletionPropagation", "dryRunOption", "maxHistory", "nameTemplate", "valuesYaml", "version"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			errs := test.spec.Validate()
+
+			var paths []string
+			for _, err := range errs {
+				paths = append(paths, err.Field)
+			}
+
+			if len(paths) != len(test.expected) {
+				t.Fatalf("expected errors for %v, got %v", test.expected, errs)
+			}
+
+			found := map[string]bool{}
+			for _, path := range paths {
+				found[path] = true
+			}
+
+			for _, path := range test.expected {
+				if !found[path] {
+					t.Errorf("expected an error for %q, got %v", path, errs)
+				}
+			}
+		})
+	}
+
+	errs := (&ChartSpec{ValuesYaml: "password: [secret"}).ValidateWithPath(field.NewPath("spec", "chart"))
+	if len(errs) != 1 || errs[0].Field != "spec.chart.valuesYaml" {
+		t.Fatalf("expected an error for 'spec.chart.valuesYaml', got %v", errs)
+	}
+
+	if errs[0].BadValue != (field.OmitValueType{}) {
+		t.Errorf("expected the values to be omitted from the error, got %v", errs[0].BadValue)
+	}
+}
+
+func TestInstallChartInvalidSpec(t *testing.T) {
+	c := newTestClient(t)
+
+	chartPath := writeTestChart(t, map[string]string{
+		"Chart.yaml":               "apiVersion: v2\nname: test-chart\nversion: 0.1.0\n",
+		"templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\n",
+	})
+
+	// Helm ignores WaitForJobs without Wait, so the spec is only rejected if validation is enabled.
+	spec := &ChartSpec{ReleaseName: "app", ChartName: chartPath, Namespace: "default", WaitForJobs: true}
+
+	_, err := c.InstallOrUpgradeChart(context.Background(), spec, &GenericHelmOptions{ValidateSpec: true})
+
+	var invalidErr *InvalidChartSpecError
+	if !errors.As(err, &invalidErr) || !errors.Is(err, ErrInvalidChartSpec) {
+		t.Fatalf("expected an InvalidChartSpecError, got %v", err)
+	}
+
+	if len(invalidErr.Errors) != 1 || invalidErr.Errors[0].Field != "waitForJobs" {
+		t.Errorf("expected an error for 'waitForJobs', got %v", invalidErr.Errors)
+	}
+
+	if _, err := c.InstallOrUpgradeChart(context.Background(), spec, nil); err != nil {
+		t.Errorf("expected the spec to be accepted without validation, got %v", err)
+	}
+}
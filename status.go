@@ -0,0 +1,326 @@
+package helmclient
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// relatedResourceSuffix marks the keys of resources that are not part of the release manifest
+// in release.Info.Resources, e.g. 'v1/Pod(related)'.
+const relatedResourceSuffix = "(related)"
+
+// ReleaseStatus returns the release with the provided name along with the readiness of every resource it owns.
+// Resources of the release manifest missing from the cluster are reported as failed.
+// Pods related to the release's resources, e.g. those of a deployment, are included as well.
+func (c *HelmClient) ReleaseStatus(ctx context.Context, name string) (*ReleaseStatus, error) {
+	client := action.NewStatus(c.ActionConfig)
+	client.ShowResources = true
+
+	rel, err := runWithContext(ctx, func() (*release.Release, error) {
+		return client.Run(name)
+	})
+	if err != nil {
+		return nil, releaseError(name, err)
+	}
+
+	manifestResources, err := parseManifest(rel.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &ReleaseStatus{Release: rel}
+	found := map[resourceKey]bool{}
+
+	for key, objects := range rel.Info.Resources {
+		related := strings.HasSuffix(key, relatedResourceSuffix)
+		version, kind, _ := strings.Cut(strings.TrimSuffix(key, relatedResourceSuffix), "/")
+		fallbackGVK := schema.GroupVersionKind{Version: version, Kind: kind}
+
+		for _, object := range objects {
+			resourceStatus, err := newResourceStatus(object, fallbackGVK, related)
+			if err != nil {
+				return nil, err
+			}
+
+			found[resourceStatus.key()] = true
+			status.Resources = append(status.Resources, *resourceStatus)
+		}
+	}
+
+	for key, resource := range manifestResources {
+		if found[key] {
+			continue
+		}
+
+		if key.namespace == "" {
+			// Namespaced resources without an explicit namespace are deployed to the release namespace.
+			if found[resourceKey{groupKind: key.groupKind, namespace: rel.Namespace, name: key.name}] {
+				continue
+			}
+		}
+
+		status.Resources = append(status.Resources, ResourceStatus{
+			APIVersion: resource.object.GetAPIVersion(),
+			Kind:       resource.object.GetKind(),
+			Namespace:  key.namespace,
+			Name:       key.name,
+			Readiness:  ResourceFailed,
+			Reason:     "resource not found",
+		})
+	}
+
+	sort.Slice(status.Resources, func(i, j int) bool {
+		a, b := status.Resources[i], status.Resources[j]
+		if a.Related != b.Related {
+			return !a.Related
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	return status, nil
+}
+
+// newResourceStatus evaluates the readiness of the provided object.
+// The fallback GVK is used for typed objects whose type information is not set.
+func newResourceStatus(object runtime.Object, fallbackGVK schema.GroupVersionKind, related bool) (*ResourceStatus, error) {
+	u, ok := object.(*unstructured.Unstructured)
+	if !ok {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %T: %w", object, err)
+		}
+		u = &unstructured.Unstructured{Object: content}
+	}
+
+	gvk := u.GroupVersionKind()
+	if gvk.Kind == "" {
+		gvk = fallbackGVK
+	}
+
+	readiness, reason := resourceReadiness(gvk.Kind, u)
+
+	return &ResourceStatus{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  u.GetNamespace(),
+		Name:       u.GetName(),
+		Readiness:  readiness,
+		Reason:     reason,
+		Related:    related,
+	}, nil
+}
+
+// resourceReadiness evaluates the readiness of a resource from its status, similar to the checks of 'helm install --wait'.
+// Resources without a known notion of readiness are considered ready unless they report a failing Ready condition.
+func resourceReadiness(kind string, u *unstructured.Unstructured) (ResourceReadiness, string) {
+	if u.GetDeletionTimestamp() != nil {
+		return ResourceInProgress, "resource is being deleted"
+	}
+
+	if observed, found, _ := unstructured.NestedInt64(u.Object, "status", "observedGeneration"); found && observed < u.GetGeneration() {
+		return ResourceInProgress, "waiting for the controller to observe the latest generation"
+	}
+
+	switch kind {
+	case "Deployment":
+		return deploymentReadiness(u)
+	case "StatefulSet", "ReplicaSet", "ReplicationController":
+		return replicasReadiness(u)
+	case "DaemonSet":
+		return daemonSetReadiness(u)
+	case "Pod":
+		return podReadiness(u)
+	case "Job":
+		return jobReadiness(u)
+	case "PersistentVolumeClaim":
+		if phase, _, _ := unstructured.NestedString(u.Object, "status", "phase"); phase != "Bound" {
+			return ResourceInProgress, fmt.Sprintf("volume claim is %s", strings.ToLower(phaseOrUnknown(phase)))
+		}
+	case "Service":
+		if serviceType, _, _ := unstructured.NestedString(u.Object, "spec", "type"); serviceType == "LoadBalancer" {
+			if ingress, _, _ := unstructured.NestedSlice(u.Object, "status", "loadBalancer", "ingress"); len(ingress) == 0 {
+				return ResourceInProgress, "waiting for the load balancer to be provisioned"
+			}
+		}
+	case "CustomResourceDefinition":
+		if status, _ := condition(u, "Established"); status != "True" {
+			return ResourceInProgress, "waiting for the CRD to be established"
+		}
+	default:
+		if status, message := condition(u, "Ready"); status == "False" {
+			return ResourceInProgress, message
+		}
+	}
+
+	return ResourceReady, ""
+}
+
+// deploymentReadiness evaluates the rollout of a Deployment.
+func deploymentReadiness(u *unstructured.Unstructured) (ResourceReadiness, string) {
+	if status, message := condition(u, "Progressing"); status == "False" {
+		return ResourceFailed, message
+	}
+
+	replicas := desiredReplicas(u)
+	updated, _, _ := unstructured.NestedInt64(u.Object, "status", "updatedReplicas")
+	available, _, _ := unstructured.NestedInt64(u.Object, "status", "availableReplicas")
+
+	switch {
+	case updated < replicas:
+		return ResourceInProgress, fmt.Sprintf("%d of %d replicas updated", updated, replicas)
+	case available < replicas:
+		return ResourceInProgress, fmt.Sprintf("%d of %d replicas available", available, replicas)
+	}
+
+	return ResourceReady, ""
+}
+
+// replicasReadiness evaluates the ready replicas of a StatefulSet, ReplicaSet or ReplicationController.
+func replicasReadiness(u *unstructured.Unstructured) (ResourceReadiness, string) {
+	replicas := desiredReplicas(u)
+	ready, _, _ := unstructured.NestedInt64(u.Object, "status", "readyReplicas")
+	if ready < replicas {
+		return ResourceInProgress, fmt.Sprintf("%d of %d replicas ready", ready, replicas)
+	}
+
+	current, _, _ := unstructured.NestedString(u.Object, "status", "currentRevision")
+	update, _, _ := unstructured.NestedString(u.Object, "status", "updateRevision")
+	if current != update {
+		return ResourceInProgress, fmt.Sprintf("rolling out revision %s", update)
+	}
+
+	return ResourceReady, ""
+}
+
+// daemonSetReadiness evaluates the rollout of a DaemonSet.
+func daemonSetReadiness(u *unstructured.Unstructured) (ResourceReadiness, string) {
+	desired, _, _ := unstructured.NestedInt64(u.Object, "status", "desiredNumberScheduled")
+	updated, _, _ := unstructured.NestedInt64(u.Object, "status", "updatedNumberScheduled")
+	ready, _, _ := unstructured.NestedInt64(u.Object, "status", "numberReady")
+
+	switch {
+	case updated < desired:
+		return ResourceInProgress, fmt.Sprintf("%d of %d pods updated", updated, desired)
+	case ready < desired:
+		return ResourceInProgress, fmt.Sprintf("%d of %d pods ready", ready, desired)
+	}
+
+	return ResourceReady, ""
+}
+
+// failingContainerReasons are the reasons of waiting containers that do not resolve without intervention.
+var failingContainerReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+}
+
+// podReadiness evaluates the phase and containers of a Pod.
+func podReadiness(u *unstructured.Unstructured) (ResourceReadiness, string) {
+	phase, _, _ := unstructured.NestedString(u.Object, "status", "phase")
+	switch phase {
+	case "Succeeded":
+		return ResourceReady, "pod completed"
+	case "Failed":
+		reason, _, _ := unstructured.NestedString(u.Object, "status", "reason")
+		return ResourceFailed, fmt.Sprintf("pod failed: %s", reason)
+	}
+
+	containerStatuses, _, _ := unstructured.NestedSlice(u.Object, "status", "containerStatuses")
+	for _, containerStatus := range containerStatuses {
+		containerStatus, ok := containerStatus.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		reason, _, _ := unstructured.NestedString(containerStatus, "state", "waiting", "reason")
+		if failingContainerReasons[reason] {
+			name, _, _ := unstructured.NestedString(containerStatus, "name")
+			return ResourceFailed, fmt.Sprintf("container %s: %s", name, reason)
+		}
+	}
+
+	if status, _ := condition(u, "Ready"); status != "True" {
+		return ResourceInProgress, fmt.Sprintf("pod is %s", strings.ToLower(phaseOrUnknown(phase)))
+	}
+
+	return ResourceReady, ""
+}
+
+// jobReadiness evaluates the completion of a Job.
+func jobReadiness(u *unstructured.Unstructured) (ResourceReadiness, string) {
+	if status, message := condition(u, "Failed"); status == "True" {
+		return ResourceFailed, message
+	}
+
+	if status, _ := condition(u, "Complete"); status != "True" {
+		return ResourceInProgress, "job has not completed"
+	}
+
+	return ResourceReady, ""
+}
+
+// desiredReplicas returns the replicas of the resource's spec, which default to 1.
+func desiredReplicas(u *unstructured.Unstructured) int64 {
+	replicas, found, _ := unstructured.NestedInt64(u.Object, "spec", "replicas")
+	if !found {
+		return 1
+	}
+
+	return replicas
+}
+
+// condition returns the status and message (or reason, if no message is set) of the provided condition type.
+func condition(u *unstructured.Unstructured, conditionType string) (string, string) {
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, c := range conditions {
+		c, ok := c.(map[string]interface{})
+		if !ok || c["type"] != conditionType {
+			continue
+		}
+
+		status, _, _ := unstructured.NestedString(c, "status")
+		message, _, _ := unstructured.NestedString(c, "message")
+		if message == "" {
+			message, _, _ = unstructured.NestedString(c, "reason")
+		}
+
+		return status, message
+	}
+
+	return "", ""
+}
+
+// phaseOrUnknown returns the provided phase, or 'Unknown' if it is empty.
+func phaseOrUnknown(phase string) string {
+	if phase == "" {
+		return "Unknown"
+	}
+
+	return phase
+}
+
+// key returns the key identifying the resource in a release manifest.
+func (s *ResourceStatus) key() resourceKey {
+	return resourceKey{
+		groupKind: schema.FromAPIVersionAndKind(s.APIVersion, s.Kind).GroupKind(),
+		namespace: s.Namespace,
+		name:      s.Name,
+	}
+}
@@ -0,0 +1,173 @@
+package helmclient
+
+import (
+	"context"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/kube"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"sigs.k8s.io/yaml"
+)
+
+func TestResourceReadiness(t *testing.T) {
+	tests := []struct {
+		name      string
+		object    string
+		readiness ResourceReadiness
+		reason    string
+	}{
+		{
+			name:      "available deployment",
+			object:    "kind: Deployment\nspec: {replicas: 2}\nstatus: {updatedReplicas: 2, availableReplicas: 2}",
+			readiness: ResourceReady,
+		},
+		{
+			name:      "rolling deployment",
+			object:    "kind: Deployment\nspec: {replicas: 2}\nstatus: {updatedReplicas: 2, availableReplicas: 1}",
+			readiness: ResourceInProgress,
+			reason:    "1 of 2 replicas available",
+		},
+		{
+			name:      "stuck deployment",
+			object:    "kind: Deployment\nstatus:\n  conditions: [{type: Progressing, status: 'False', reason: ProgressDeadlineExceeded}]",
+			readiness: ResourceFailed,
+			reason:    "ProgressDeadlineExceeded",
+		},
+		{
+			name:      "unobserved generation",
+			object:    "kind: Deployment\nmetadata: {generation: 3}\nstatus: {observedGeneration: 2}",
+			readiness: ResourceInProgress,
+			reason:    "waiting for the controller to observe the latest generation",
+		},
+		{
+			name:      "crashing pod",
+			object:    "kind: Pod\nstatus:\n  phase: Running\n  containerStatuses: [{name: app, state: {waiting: {reason: CrashLoopBackOff}}}]",
+			readiness: ResourceFailed,
+			reason:    "container app: CrashLoopBackOff",
+		},
+		{
+			name:      "failed job",
+			object:    "kind: Job\nstatus:\n  conditions: [{type: Failed, status: 'True', message: BackoffLimitExceeded}]",
+			readiness: ResourceFailed,
+			reason:    "BackoffLimitExceeded",
+		},
+		{
+			name:      "pending volume claim",
+			object:    "kind: PersistentVolumeClaim\nstatus: {phase: Pending}",
+			readiness: ResourceInProgress,
+			reason:    "volume claim is pending",
+		},
+		{
+			name:      "custom resource not ready",
+			object:    "kind: Certificate\nstatus:\n  conditions: [{type: Ready, status: 'False', message: Issuing}]",
+			readiness: ResourceInProgress,
+			reason:    "Issuing",
+		},
+		{
+			name:      "config map",
+			object:    "kind: ConfigMap",
+			readiness: ResourceReady,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The JSON decoder of apimachinery decodes numbers as int64, like objects retrieved from the API server.
+			data, err := yaml.YAMLToJSON([]byte(tt.object))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			u := &unstructured.Unstructured{}
+			if err := utiljson.Unmarshal(data, &u.Object); err != nil {
+				t.Fatal(err)
+			}
+
+			readiness, reason := resourceReadiness(u.GetKind(), u)
+			if readiness != tt.readiness || reason != tt.reason {
+				t.Errorf("expected %q (%q), got %q (%q)", tt.readiness, tt.reason, readiness, reason)
+			}
+		})
+	}
+}
+
+// resourcesKubeClient returns the configured resources as the current state of the cluster.
+type resourcesKubeClient struct {
+	kubefake.PrintingKubeClient
+	resources map[string][]runtime.Object
+}
+
+func (c *resourcesKubeClient) Get(kube.ResourceList, bool) (map[string][]runtime.Object, error) {
+	return c.resources, nil
+}
+
+func TestReleaseStatus(t *testing.T) {
+	c := newTestClient(t)
+
+	deployment := &unstructured.Unstructured{}
+	deployment.SetAPIVersion("apps/v1")
+	deployment.SetKind("Deployment")
+	deployment.SetNamespace("default")
+	deployment.SetName("app")
+	_ = unstructured.SetNestedField(deployment.Object, int64(1), "status", "updatedReplicas")
+	_ = unstructured.SetNestedField(deployment.Object, int64(1), "status", "availableReplicas")
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app-1"},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending},
+	}
+
+	c.ActionConfig.KubeClient = &resourcesKubeClient{
+		resources: map[string][]runtime.Object{
+			"v1/Deployment":   {deployment},
+			"v1/Pod(related)": {pod},
+		},
+	}
+
+	err := c.ActionConfig.Releases.Create(&release.Release{
+		Name:      "test",
+		Namespace: "default",
+		Version:   1,
+		Info:      &release.Info{Status: release.StatusDeployed},
+		Manifest: "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: app\n---\n" +
+			"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: missing\n",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := c.ReleaseStatus(context.Background(), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"ConfigMap missing: failed (resource not found)",
+		"Deployment default/app: ready",
+		"Pod default/app-1: in-progress (pod is pending)",
+	}
+
+	if len(status.Resources) != len(expected) {
+		t.Fatalf("expected %d resources, got %v", len(expected), status.Resources)
+	}
+
+	for i, resource := range status.Resources {
+		if resource.String() != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], resource.String())
+		}
+	}
+
+	if !status.Resources[2].Related || status.Resources[2].APIVersion != "v1" {
+		t.Errorf("expected the pod to be reported as related resource, got %+v", status.Resources[2])
+	}
+
+	if status.Ready() {
+		t.Error("expected the release not to be ready")
+	}
+}
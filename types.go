@@ -1,6 +1,7 @@
 package helmclient
 
 import (
+	"fmt"
 	"io"
 	"time"
 
@@ -11,7 +12,9 @@ import (
 	"helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/getter"
 	"helm.sh/helm/v3/pkg/postrender"
+	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/repo"
+	"helm.sh/helm/v3/pkg/storage/driver"
 
 	"github.com/mittwald/go-helm-client/values"
 )
@@ -42,8 +45,30 @@ type Options struct {
 	DebugLog         action.DebugLog
 	RegistryConfig   string
 	Output           io.Writer
+	// StorageDriver is the driver storing releases, see the StorageDriver constants.
+	// It defaults to the HELM_DRIVER environment variable, or StorageDriverSecret if that is unset.
+	StorageDriver string
+	// SQLConnectionString is the connection string of StorageDriverSQL.
+	// It defaults to the HELM_DRIVER_SQL_CONNECTION_STRING environment variable.
+	SQLConnectionString string
+	// Driver is a custom driver storing releases. It takes precedence over StorageDriver.
+	// The driver is shared by all clients derived via WithNamespace, so it must not be bound to a namespace.
+	Driver driver.Driver
+	// OperationObserver receives the duration and outcome of the operations performed by the client.
+	OperationObserver OperationObserver
 }
 
+const (
+	// StorageDriverSecret stores releases in secrets of the release namespace.
+	StorageDriverSecret = "secret"
+	// StorageDriverConfigMap stores releases in config maps of the release namespace.
+	StorageDriverConfigMap = "configmap"
+	// StorageDriverMemory stores releases in memory, which is useful for tests and dry runs.
+	StorageDriverMemory = "memory"
+	// StorageDriverSQL stores releases in a PostgreSQL database, see Options.SQLConnectionString.
+	StorageDriverSQL = "sql"
+)
+
 // RESTClientOption is a function that can be used to set the RESTClientOptions of a HelmClient.
 type RESTClientOption func(*rest.Config)
 
@@ -65,6 +90,16 @@ func Burst(v int) RESTClientOption {
 	}
 }
 
+// releaseStorageOptions defines the driver storing the releases of a client.
+// The zero value uses StorageDriverSecret.
+type releaseStorageOptions struct {
+	driverName          string
+	sqlConnectionString string
+	driver              driver.Driver
+	// memory holds the releases of StorageDriverMemory, shared with the clients derived via WithNamespace.
+	memory *memoryReleases
+}
+
 // RESTClientGetter defines the values of a helm REST client.
 type RESTClientGetter struct {
 	namespace  string
@@ -79,12 +114,17 @@ type HelmClient struct {
 	// Settings defines the environment settings of a client.
 	Settings  *cli.EnvSettings
 	Providers getter.Providers
-	storage   *repo.File
+	storage   *repositoryStorage
+	// registryCredentials holds the credentials of RegistryLogin.
+	registryCredentials *registryCredentials
 	// ActionConfig is the helm action configuration.
 	ActionConfig *action.Configuration
-	linting      bool
-	output       io.Writer
-	DebugLog     action.DebugLog
+	// releaseStorage is the release storage driver used for clients derived via WithNamespace.
+	releaseStorage    releaseStorageOptions
+	operationObserver OperationObserver
+	linting           bool
+	output            io.Writer
+	DebugLog          action.DebugLog
 }
 
 func (c *HelmClient) GetSettings() *cli.EnvSettings {
@@ -97,7 +137,20 @@ func (c *HelmClient) GetProviders() getter.Providers {
 
 type GenericHelmOptions struct {
 	PostRenderer postrender.PostRenderer
-	RollBack     RollBack
+	// RollBack is called if an upgrade failed without creating a revision of the release.
+	RollBack RollBack
+	// FailedReleaseRollBack is called with the details of any failed upgrade. It takes precedence over RollBack.
+	FailedReleaseRollBack FailedReleaseRollBack
+	// LintOptions configures the linting performed before installing or upgrading a chart, if enabled via Options.Linting.
+	LintOptions *LintOptions
+	// ProgressObserver receives the progress of installing or upgrading a chart.
+	ProgressObserver ProgressObserver
+	// RecoverPendingRelease enables InstallOrUpgradeChart to recover the release if it is stuck in a pending state,
+	// see HelmClient.RecoverRelease. Recovery is disabled if unset.
+	RecoverPendingRelease *RecoverReleaseOptions
+	// ValidateSpec rejects an invalid ChartSpec with an InvalidChartSpecError before contacting the cluster,
+	// see ChartSpec.Validate. It is disabled if unset, since helm accepts some of the reported specs, e.g. WaitForJobs without Wait.
+	ValidateSpec bool
 }
 
 type HelmTemplateOptions struct {
@@ -106,6 +159,241 @@ type HelmTemplateOptions struct {
 	APIVersions chartutil.VersionSet
 }
 
+// ChartRepoOptions defines the options used for adding or updating a chart repository.
+type ChartRepoOptions struct {
+	// ForceUpdate downloads the repository index and persists the entry even if the entry is unchanged.
+	ForceUpdate bool
+}
+
+// ChartRepoChange describes what happened to a chart repository entry when adding or updating it.
+type ChartRepoChange string
+
+const (
+	// ChartRepoAdded indicates that the repository did not exist before.
+	ChartRepoAdded ChartRepoChange = "added"
+	// ChartRepoUpdated indicates that the entry of an existing repository has changed.
+	ChartRepoUpdated ChartRepoChange = "updated"
+	// ChartRepoUnchanged indicates that the entry of the repository was already up to date.
+	ChartRepoUnchanged ChartRepoChange = "unchanged"
+)
+
+// UpdateChartReposOptions defines the options used for updating chart repositories.
+type UpdateChartReposOptions struct {
+	// Concurrency is the maximum number of repositories updated in parallel. Defaults to 8.
+	Concurrency int
+}
+
+// ChartRepoUpdateResult is the result of updating a single chart repository.
+type ChartRepoUpdateResult struct {
+	Name string
+	URL  string
+	// Duration is the time it took to download and load the index.
+	Duration time.Duration
+	// Err is the error that occurred while updating the repository, or nil if the update succeeded.
+	Err error
+	// Entries is the number of charts in the downloaded index.
+	// OCI repositories do not have an index, so no charts are counted for them.
+	Entries int
+}
+
+// ApplyFailurePolicy defines how ApplyReleases proceeds after a release failed.
+type ApplyFailurePolicy string
+
+const (
+	// ApplyStopOnFailure stops applying further releases after a release failed.
+	// Releases that are already being applied are completed.
+	ApplyStopOnFailure ApplyFailurePolicy = "stop"
+	// ApplyContinueOnFailure applies all releases that do not depend on a failed release.
+	ApplyContinueOnFailure ApplyFailurePolicy = "continue"
+)
+
+// ApplyReleasesOptions defines the options of ApplyReleases.
+type ApplyReleasesOptions struct {
+	// Concurrency limits the number of releases applied in parallel. Defaults to 4.
+	Concurrency int
+	// FailurePolicy defines how to proceed after a release failed. Defaults to ApplyStopOnFailure.
+	FailurePolicy ApplyFailurePolicy
+	// HelmOptions are passed to every install or upgrade.
+	HelmOptions *GenericHelmOptions
+}
+
+// ReleaseApplyResult is the result of applying a single release via ApplyReleases.
+type ReleaseApplyResult struct {
+	ReleaseName string
+	Namespace   string
+	// Release is the installed or upgraded release.
+	Release *release.Release
+	// Err is the error that occurred while applying the release, or nil if it was deployed.
+	Err error
+	// Skipped indicates that the release was not applied, because a dependency failed or applying was stopped.
+	Skipped bool
+	// Duration is the time it took to install or upgrade the release.
+	Duration time.Duration
+}
+
+// ReleaseSet declares chart repositories and releases, see ParseReleaseSet and Reconcile.
+type ReleaseSet struct {
+	// Name identifies the releases applied by the set, see ReleaseSetLabel.
+	Name string `json:"name"`
+	// Repositories are added or updated before the releases are applied.
+	Repositories []repo.Entry `json:"repositories,omitempty"`
+	// Releases are installed or upgraded in the order of their dependencies.
+	Releases []ChartSpec `json:"releases,omitempty"`
+}
+
+// ReconcileOptions defines the options of Reconcile.
+type ReconcileOptions struct {
+	ApplyReleasesOptions
+	// Prune uninstalls releases applied by the release set that are no longer part of it.
+	Prune bool
+}
+
+// ReconcileResult is the result of reconciling a release set.
+type ReconcileResult struct {
+	// Repositories reports what happened to each repository of the set by name.
+	Repositories map[string]ChartRepoChange
+	// Releases are the results of applying the releases of the set, in the order of the set.
+	Releases []*ReleaseApplyResult
+	// Pruned are the releases that were uninstalled, because they were no longer part of the set.
+	Pruned []*release.Release
+}
+
+// SearchChartsOptions defines the options used for searching charts in the cached repository indexes.
+type SearchChartsOptions struct {
+	// Keyword is matched case-insensitively against the chart name, description and keywords.
+	Keyword string
+	// Regexp is matched against the chart name, including the repository prefix, e.g. 'stable/nginx'.
+	Regexp string
+	// Version is a semver constraint the chart version must satisfy, e.g. '^1.2.0'.
+	Version string
+	// Devel includes development versions if no Version is set.
+	Devel bool
+	// AllVersions returns all matching versions instead of only the latest one per chart.
+	AllVersions bool
+}
+
+// ChartSearchResult is a chart version found by SearchCharts.
+type ChartSearchResult struct {
+	// Name is the name of the chart, prefixed with its repository, e.g. 'stable/nginx'.
+	Name string
+	// Repository is the name of the repository containing the chart.
+	Repository string
+	// Chart is the index entry of the chart version.
+	Chart *repo.ChartVersion
+}
+
+// RegistryOptions defines the options used for connecting to an OCI registry.
+type RegistryOptions struct {
+	// PlainHTTP connects to the registry via HTTP instead of HTTPS.
+	PlainHTTP bool
+	// InsecureSkipTLSVerify skips the verification of the registry's TLS certificate.
+	InsecureSkipTLSVerify bool
+	// CertFile and KeyFile identify the client via a TLS certificate.
+	CertFile string
+	KeyFile  string
+	// CAFile is the CA bundle used to verify the registry's TLS certificate.
+	CAFile string
+}
+
+// RegistryLoginOptions defines the options used for logging in to an OCI registry.
+type RegistryLoginOptions struct {
+	RegistryOptions
+	Username string
+	Password string
+}
+
+// PullChartOptions defines the options used for pulling a chart from an OCI registry.
+type PullChartOptions struct {
+	RegistryOptions
+	// Version is the version or semver constraint of the chart. Defaults to the latest version.
+	Version string
+	// DestDir is the directory the chart is saved to. Defaults to the current directory.
+	DestDir string
+	// Untar expands the chart into DestDir instead of saving the chart archive.
+	Untar bool
+}
+
+// PackageChartOptions defines the options used for packaging a chart.
+type PackageChartOptions struct {
+	// Version overrides the version of the chart.
+	Version string
+	// AppVersion overrides the app version of the chart.
+	AppVersion string
+	// Destination is the directory the chart archive is written to. Defaults to the current directory.
+	Destination string
+	// DependencyUpdate updates the dependencies of the chart from its Chart.yaml before packaging it.
+	DependencyUpdate bool
+	// Sign signs the chart archive and writes a provenance file next to it.
+	Sign bool
+	// Keyring is the path of the keyring containing the signing key.
+	Keyring string
+	// Key is the name of the signing key in the keyring.
+	Key string
+	// Passphrase decrypts the signing key, if it is encrypted.
+	Passphrase []byte
+}
+
+// ChartVerification is the result of verifying a chart archive against its provenance file.
+type ChartVerification struct {
+	// FileName is the name of the verified chart archive.
+	FileName string
+	// FileHash is the digest of the chart archive, prefixed with the hash algorithm, e.g. 'sha256:...'.
+	FileHash string
+	// SignedBy are the identities of the signing key, e.g. 'Jane Doe <jane@example.com>'.
+	SignedBy []string
+	// Fingerprint is the fingerprint of the signing key.
+	Fingerprint string
+}
+
+// LintOptions defines the options used for linting a chart.
+type LintOptions struct {
+	// Strict fails linting on warnings as well as on errors.
+	Strict bool
+	// WithSubcharts lints the dependencies of the chart as well.
+	WithSubcharts bool
+	// SkipSchemaValidation disables the validation of the values against the chart's JSON schema.
+	SkipSchemaValidation bool
+	// Namespace is the namespace the chart is rendered for.
+	// Defaults to the namespace of the linted ChartSpec.
+	Namespace string
+	// KubeVersion is the Kubernetes version the chart is rendered for.
+	// Defaults to the version known to helm.
+	KubeVersion *chartutil.KubeVersion
+}
+
+// LintSeverity describes the severity of a lint message.
+type LintSeverity string
+
+const (
+	LintSeverityUnknown LintSeverity = "UNKNOWN"
+	LintSeverityInfo    LintSeverity = "INFO"
+	LintSeverityWarning LintSeverity = "WARNING"
+	LintSeverityError   LintSeverity = "ERROR"
+)
+
+// LintMessage is a single message reported by the linter.
+type LintMessage struct {
+	Severity LintSeverity
+	// Path is the path of the file within the chart the message refers to.
+	Path    string
+	Message string
+}
+
+// String returns the message formatted like the output of 'helm lint'.
+func (m LintMessage) String() string {
+	return fmt.Sprintf("[%s] %s: %s", m.Severity, m.Path, m.Message)
+}
+
+// LintReport is the result of linting a chart.
+type LintReport struct {
+	// ChartPath is the path of the linted chart.
+	ChartPath string
+	// Messages are all messages reported by the linter.
+	Messages []LintMessage
+	// Failed indicates whether linting failed, taking LintOptions.Strict into account.
+	Failed bool
+}
+
 // ChartSpec defines the values of a helm chart
 // +kubebuilder:object:generate:=true
 type ChartSpec struct {
@@ -142,6 +430,44 @@ type ChartSpec struct {
 	// 'Wait' has to be specified for this to take effect.
 	// The timeout may be specified via the 'Timeout' field.
 	WaitForJobs bool `json:"waitForJobs,omitempty"`
+	// RepoURL is the URL of the chart repository the chart is looked up in.
+	// Unlike repositories added via AddOrUpdateChartRepo, it is not stored in the repository config.
+	// +optional
+	RepoURL string `json:"repoURL,omitempty"`
+	// Username is used to authenticate against the chart repository or OCI registry.
+	// +optional
+	Username string `json:"username,omitempty"`
+	// Password is used to authenticate against the chart repository or OCI registry.
+	// +optional
+	Password string `json:"password,omitempty"`
+	// PassCredentialsAll indicates whether to pass the credentials to all domains,
+	// e.g. if the charts of the repository are served by a different host.
+	// +optional
+	PassCredentialsAll bool `json:"passCredentialsAll,omitempty"`
+	// CAFile is the CA bundle used to verify the TLS certificate of the chart repository or OCI registry.
+	// +optional
+	CAFile string `json:"caFile,omitempty"`
+	// CertFile is the TLS client certificate used to authenticate against the chart repository or OCI registry.
+	// +optional
+	CertFile string `json:"certFile,omitempty"`
+	// KeyFile is the key of the TLS client certificate.
+	// +optional
+	KeyFile string `json:"keyFile,omitempty"`
+	// InsecureSkipTLSVerify indicates whether to skip the verification of the TLS certificate
+	// of the chart repository or OCI registry.
+	// +optional
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+	// PlainHTTP indicates whether to connect to the chart repository or OCI registry via HTTP instead of HTTPS.
+	// +optional
+	PlainHTTP bool `json:"plainHTTP,omitempty"`
+	// Verify indicates whether to verify the chart against its provenance file before using it.
+	// Charts without a valid signature of a key in the keyring are refused.
+	// +optional
+	Verify bool `json:"verify,omitempty"`
+	// Keyring is the path of the keyring containing the public keys used for verification.
+	// Defaults to the public GnuPG keyring.
+	// +optional
+	Keyring string `json:"keyring,omitempty"`
 	// DependencyUpdate indicates whether to update the chart release if the dependencies have changed.
 	// +optional
 	DependencyUpdate bool `json:"dependencyUpdate,omitempty"`
@@ -209,4 +535,384 @@ type ChartSpec struct {
 	// Valid options are orphan, foreground, background. Defaulting to background.
 	// +optional
 	DeletionPropagation string `json:"deletionPropagation,omitempty"`
+	// DependsOn lists the releases that must be deployed before this release when applied via ApplyReleases.
+	// Dependencies are referenced by release name in the same namespace or as 'namespace/name'.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// DiffChangeType describes how an entry changed between two revisions of a release.
+type DiffChangeType string
+
+const (
+	// DiffAdded indicates an entry that is only present in the new revision.
+	DiffAdded DiffChangeType = "added"
+	// DiffRemoved indicates an entry that is only present in the current revision.
+	DiffRemoved DiffChangeType = "removed"
+	// DiffChanged indicates an entry that is present in both revisions, but differs.
+	DiffChanged DiffChangeType = "changed"
+)
+
+// ReleaseDiff describes the changes an install or upgrade of a release would apply.
+type ReleaseDiff struct {
+	ReleaseName string
+	Namespace   string
+	// Installed indicates whether the release already exists.
+	// If it does not, every resource of the chart is reported as added.
+	Installed bool
+	// Added lists resources that would be created.
+	Added []ResourceDiff
+	// Removed lists resources that would be deleted.
+	Removed []ResourceDiff
+	// Changed lists resources that would be modified.
+	Changed []ResourceDiff
+	// Values lists the changes of the user supplied values.
+	Values []ValueChange
+}
+
+// HasChanges returns true if applying the release would change any resource or value.
+func (d *ReleaseDiff) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Changed) > 0 || len(d.Values) > 0
+}
+
+// ResourceDiff describes the change of a single resource of a release.
+type ResourceDiff struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+	// Diff is a unified diff of the resource's YAML representation.
+	Diff string
+}
+
+// String returns a human-readable identifier of the resource.
+func (d ResourceDiff) String() string {
+	if d.Namespace == "" {
+		return fmt.Sprintf("%s/%s (%s)", d.Kind, d.Name, d.APIVersion)
+	}
+
+	return fmt.Sprintf("%s/%s/%s (%s)", d.Namespace, d.Kind, d.Name, d.APIVersion)
+}
+
+// ValueChange describes the change of a single value of a release.
+type ValueChange struct {
+	// Path is the dot-separated path of the value, e.g. "image.tag".
+	Path string
+	Type DiffChangeType
+	// Old is the current value, which is nil if the value was added.
+	Old interface{}
+	// New is the prospective value, which is nil if the value was removed.
+	New interface{}
+}
+
+// ReleaseDrift describes how the live resources of a release differ from its manifest.
+type ReleaseDrift struct {
+	ReleaseName string
+	Namespace   string
+	// Revision is the revision of the release whose manifest was compared.
+	Revision int
+	// Resources lists the resources that were deleted or modified out-of-band.
+	Resources []ResourceDrift
+}
+
+// Drifted returns true if any resource of the release differs from its manifest.
+func (d *ReleaseDrift) Drifted() bool {
+	return len(d.Resources) > 0
+}
+
+// ResourceDrift describes how a single live resource differs from the release manifest.
+type ResourceDrift struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+	// Deleted indicates that the resource does not exist anymore.
+	Deleted bool
+	// Fields lists the fields of the manifest whose live values differ.
+	Fields []FieldDrift
+}
+
+func (d ResourceDrift) String() string {
+	return ResourceDiff{APIVersion: d.APIVersion, Kind: d.Kind, Namespace: d.Namespace, Name: d.Name}.String()
+}
+
+// FieldDrift describes a single field of a resource whose live value differs from the release manifest.
+type FieldDrift struct {
+	// Path is the path of the field, e.g. "spec.template.spec.containers[0].image".
+	Path string
+	// Type is DiffChanged if the value was modified and DiffRemoved if the field was removed.
+	Type DiffChangeType
+	// Expected is the value of the release manifest.
+	Expected interface{}
+	// Actual is the live value, which is nil if the field was removed.
+	Actual interface{}
+}
+
+// ResourceReadiness describes whether a resource of a release is ready.
+type ResourceReadiness string
+
+const (
+	// ResourceReady indicates that the resource is ready, e.g. all replicas of a deployment are available.
+	ResourceReady ResourceReadiness = "ready"
+	// ResourceInProgress indicates that the resource is not ready yet, e.g. because a rollout is in progress.
+	ResourceInProgress ResourceReadiness = "in-progress"
+	// ResourceFailed indicates that the resource will not become ready without intervention.
+	ResourceFailed ResourceReadiness = "failed"
+)
+
+// ReleaseStatus is a release along with the readiness of its resources.
+type ReleaseStatus struct {
+	Release   *release.Release
+	Resources []ResourceStatus
+}
+
+// Ready returns true if all resources of the release are ready.
+func (s *ReleaseStatus) Ready() bool {
+	for _, resource := range s.Resources {
+		if resource.Readiness != ResourceReady {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ResourceStatus is the readiness of a single resource of a release.
+type ResourceStatus struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+	Readiness  ResourceReadiness
+	// Reason explains why the resource is not ready.
+	Reason string
+	// Related indicates that the resource is not part of the release manifest, but belongs to one of its resources,
+	// e.g. a pod of a deployment.
+	Related bool
+}
+
+func (s ResourceStatus) String() string {
+	name := s.Name
+	if s.Namespace != "" {
+		name = s.Namespace + "/" + s.Name
+	}
+
+	if s.Reason == "" {
+		return fmt.Sprintf("%s %s: %s", s.Kind, name, s.Readiness)
+	}
+
+	return fmt.Sprintf("%s %s: %s (%s)", s.Kind, name, s.Readiness, s.Reason)
+}
+
+// ProgressEventType is the type of a ProgressEvent.
+type ProgressEventType string
+
+const (
+	// ProgressChartFetched is reported once the chart has been located and loaded.
+	ProgressChartFetched ProgressEventType = "chart-fetched"
+	// ProgressDependenciesUpdated is reported once missing chart dependencies have been downloaded.
+	ProgressDependenciesUpdated ProgressEventType = "dependencies-updated"
+	// ProgressLinted is reported once the chart passed linting.
+	ProgressLinted ProgressEventType = "linted"
+	// ProgressCRDsUpgraded is reported once the CRDs of the chart have been upgraded.
+	ProgressCRDsUpgraded ProgressEventType = "crds-upgraded"
+	// ProgressHookStarted is reported when helm creates the resource of a hook.
+	ProgressHookStarted ProgressEventType = "hook-started"
+	// ProgressHookFinished is reported when helm finished waiting for a hook, successfully or not.
+	ProgressHookFinished ProgressEventType = "hook-finished"
+	// ProgressResourcesApplied is reported when helm created or updated the resources of the release.
+	ProgressResourcesApplied ProgressEventType = "resources-applied"
+	// ProgressWaiting is reported when helm starts waiting for the resources of the release to become ready.
+	ProgressWaiting ProgressEventType = "waiting"
+	// ProgressReleaseRecovered is reported when a release stuck in a pending state was recovered before upgrading it.
+	ProgressReleaseRecovered ProgressEventType = "release-recovered"
+)
+
+// ProgressEvent is a step of installing or upgrading a release.
+type ProgressEvent struct {
+	Type        ProgressEventType
+	ReleaseName string
+	Time        time.Time
+	// Message is a human-readable description of the event.
+	Message string
+	// Resources is the number of resources applied or waited on.
+	Resources int
+	// Hook is the name of the hook resource of hook events.
+	Hook string
+	// Err is set if a hook failed.
+	Err error
+}
+
+// ProgressObserver receives the progress of installing or upgrading a release.
+// Events are delivered synchronously, so observers should return quickly.
+type ProgressObserver interface {
+	Progress(event ProgressEvent)
+}
+
+// ProgressObserverFunc is a function implementing ProgressObserver.
+type ProgressObserverFunc func(event ProgressEvent)
+
+// Progress calls f(event).
+func (f ProgressObserverFunc) Progress(event ProgressEvent) {
+	f(event)
+}
+
+// RecoveryStrategy defines how a release stuck in a pending state is recovered.
+type RecoveryStrategy string
+
+const (
+	// RecoverMarkFailed marks the pending revision as failed, so that the release can be upgraded again.
+	RecoverMarkFailed RecoveryStrategy = "mark-failed"
+	// RecoverRollback marks the pending revision as failed and rolls back to the last deployed revision.
+	// Releases without a deployed revision, e.g. those stuck in their first installation, are only marked as failed.
+	RecoverRollback RecoveryStrategy = "rollback"
+)
+
+// RecoverReleaseOptions defines the options used for recovering a release stuck in a pending state.
+type RecoverReleaseOptions struct {
+	// MinAge is the age a pending revision must have to be considered stale. It defaults to five minutes.
+	// Revisions younger than that are assumed to belong to an operation that is still in progress.
+	MinAge time.Duration
+	// Strategy defines how a stale revision is recovered. It defaults to RecoverMarkFailed.
+	Strategy RecoveryStrategy
+}
+
+// RecoveryAction describes what was done to recover a release.
+type RecoveryAction string
+
+const (
+	// RecoveryNone indicates that the release was left untouched, because it was not stuck in a pending state
+	// or its pending revision was not stale yet.
+	RecoveryNone RecoveryAction = "none"
+	// RecoveryMarkedFailed indicates that the pending revision was marked as failed.
+	RecoveryMarkedFailed RecoveryAction = "marked-failed"
+	// RecoveryRolledBack indicates that the pending revision was marked as failed and the release was rolled back.
+	RecoveryRolledBack RecoveryAction = "rolled-back"
+)
+
+// ReleaseRecovery describes the recovery of a release stuck in a pending state.
+type ReleaseRecovery struct {
+	ReleaseName string
+	Namespace   string
+	Action      RecoveryAction
+	// Revision is the latest revision of the release when recovery started.
+	Revision int
+	// Status is the status of that revision before it was recovered.
+	Status release.Status
+	// Age is the time since that revision was created.
+	Age time.Duration
+	// RollbackRevision is the revision the release was rolled back to, if it was rolled back.
+	RollbackRevision int
+}
+
+func (r *ReleaseRecovery) String() string {
+	switch r.Action {
+	case RecoveryMarkedFailed:
+		return fmt.Sprintf("marked revision %d of release %q, %s for %s, as failed", r.Revision, r.ReleaseName, r.Status, r.Age.Round(time.Second))
+	case RecoveryRolledBack:
+		return fmt.Sprintf("rolled back release %q from revision %d, %s for %s, to revision %d", r.ReleaseName, r.Revision, r.Status, r.Age.Round(time.Second), r.RollbackRevision)
+	}
+
+	return fmt.Sprintf("left revision %d of release %q, %s for %s, untouched", r.Revision, r.ReleaseName, r.Status, r.Age.Round(time.Second))
+}
+
+// RollbackOptions defines the options used for rolling back a release.
+type RollbackOptions struct {
+	DisableHooks  bool
+	DryRun        bool
+	Timeout       time.Duration
+	CleanupOnFail bool
+	Force         bool
+	MaxHistory    int
+	Recreate      bool
+	Wait          bool
+	WaitForJobs   bool
+}
+
+// Operation is an operation of a client reported to an OperationObserver.
+type Operation string
+
+const (
+	// OperationInstall is reported for installing a release.
+	OperationInstall Operation = "install"
+	// OperationUpgrade is reported for upgrading a release.
+	OperationUpgrade Operation = "upgrade"
+	// OperationUninstall is reported for uninstalling a release.
+	OperationUninstall Operation = "uninstall"
+	// OperationRollback is reported for rolling back a release.
+	OperationRollback Operation = "rollback"
+	// OperationTemplate is reported for rendering a chart via TemplateChart.
+	OperationTemplate Operation = "template"
+	// OperationLint is reported for linting a chart, including the linting performed before installs and upgrades.
+	OperationLint Operation = "lint"
+	// OperationRepoAdd is reported for adding or updating a chart repository via AddOrUpdateChartRepo.
+	OperationRepoAdd Operation = "repo-add"
+	// OperationRepoUpdate is reported for downloading the index of a chart repository via UpdateChartRepos.
+	OperationRepoUpdate Operation = "repo-update"
+	// OperationChartFetch is reported for locating and loading a chart.
+	OperationChartFetch Operation = "chart-fetch"
+	// OperationCRDUpgrade is reported for upgrading the CRDs of a chart.
+	OperationCRDUpgrade Operation = "crd-upgrade"
+)
+
+// OperationOutcome describes whether an operation succeeded.
+type OperationOutcome string
+
+const (
+	// OperationSucceeded indicates that the operation succeeded.
+	OperationSucceeded OperationOutcome = "success"
+	// OperationFailed indicates that the operation returned an error.
+	OperationFailed OperationOutcome = "failure"
+)
+
+// ErrorClass is a coarse classification of the error of a failed operation, see ClassifyError.
+type ErrorClass string
+
+const (
+	// ErrorClassNone is the class of successful operations.
+	ErrorClassNone ErrorClass = ""
+	// ErrorClassCanceled indicates that the context of the operation was canceled.
+	ErrorClassCanceled ErrorClass = "canceled"
+	// ErrorClassTimeout indicates that the operation timed out, e.g. while waiting for resources to become ready.
+	ErrorClassTimeout ErrorClass = "timeout"
+	// ErrorClassNotFound indicates that a release, chart repository or resource does not exist.
+	ErrorClassNotFound ErrorClass = "not-found"
+	// ErrorClassInvalid indicates that the chart spec or chart is invalid.
+	ErrorClassInvalid ErrorClass = "invalid"
+	// ErrorClassLint indicates that the chart did not pass linting.
+	ErrorClassLint ErrorClass = "lint"
+	// ErrorClassConflict indicates a conflict with existing resources or another operation on the release.
+	ErrorClassConflict ErrorClass = "conflict"
+	// ErrorClassOther is the class of all other errors.
+	ErrorClassOther ErrorClass = "other"
+)
+
+// OperationEvent describes an operation performed by a client.
+type OperationEvent struct {
+	Operation Operation
+	// ReleaseName is the name of the release the operation was performed on, if any.
+	ReleaseName string
+	Namespace   string
+	// Chart is the name of the chart the operation was performed with, if any.
+	Chart string
+	// Repository is the name of the chart repository of OperationRepoAdd and OperationRepoUpdate.
+	Repository string
+	Duration   time.Duration
+	Outcome    OperationOutcome
+	ErrorClass ErrorClass
+	Err        error
+}
+
+// OperationObserver receives the operations performed by a client, e.g. to record metrics.
+// Events are delivered synchronously and possibly concurrently, so observers should return quickly.
+type OperationObserver interface {
+	ObserveOperation(event OperationEvent)
+}
+
+// OperationObserverFunc is a function implementing OperationObserver.
+type OperationObserverFunc func(event OperationEvent)
+
+// ObserveOperation calls f(event).
+func (f OperationObserverFunc) ObserveOperation(event OperationEvent) {
+	f(event)
 }
@@ -0,0 +1,53 @@
+package helmclient
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"helm.sh/helm/v3/pkg/downloader"
+	"k8s.io/client-go/util/homedir"
+)
+
+// VerifyChart verifies the chart archive at 'chartArchive' against its provenance file using the public keys of 'keyring'.
+// If 'keyring' is empty, the default GnuPG keyring is used. The returned ChartVerification identifies the signer.
+func (c *HelmClient) VerifyChart(chartArchive string, keyring string) (*ChartVerification, error) {
+	if keyring == "" {
+		keyring = defaultKeyring()
+	}
+
+	verification, err := downloader.VerifyChart(chartArchive, keyring)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %w", ErrChartVerificationFailed, chartArchive, err)
+	}
+
+	result := &ChartVerification{
+		FileName: verification.FileName,
+		FileHash: verification.FileHash,
+	}
+
+	if signer := verification.SignedBy; signer != nil {
+		for identity := range signer.Identities {
+			result.SignedBy = append(result.SignedBy, identity)
+		}
+		sort.Strings(result.SignedBy)
+
+		if signer.PrimaryKey != nil {
+			result.Fingerprint = fmt.Sprintf("%X", signer.PrimaryKey.Fingerprint)
+		}
+	}
+
+	c.DebugLog("chart %q is signed by %v using key %s", chartArchive, result.SignedBy, result.Fingerprint)
+
+	return result, nil
+}
+
+// defaultKeyring returns the path of the public GnuPG keyring, which is used for verification if no keyring is configured.
+func defaultKeyring() string {
+	if gnupgHome := os.Getenv("GNUPGHOME"); gnupgHome != "" {
+		return filepath.Join(gnupgHome, "pubring.gpg")
+	}
+
+	return filepath.Join(homedir.HomeDir(), ".gnupg", "pubring.gpg")
+}
@@ -0,0 +1,82 @@
+package helmclient
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+func TestVerifyChart(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	chartPath := writeTestChart(t, map[string]string{
+		"Chart.yaml":               "apiVersion: v2\nname: test-chart\nversion: 0.1.0\n",
+		"templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\n",
+	})
+
+	keyring := writeTestKeyring(t, "signer")
+
+	signedArchive, err := c.PackageChart(ctx, chartPath, &PackageChartOptions{
+		Destination: t.TempDir(),
+		Sign:        true,
+		Keyring:     keyring,
+		Key:         "signer",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verification, err := c.VerifyChart(signedArchive, keyring)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(verification.SignedBy) != 1 || verification.SignedBy[0] != "signer <signer@example.com>" {
+		t.Errorf("expected the chart to be signed by the test key, got %v", verification.SignedBy)
+	}
+
+	if !strings.HasPrefix(verification.FileHash, "sha256:") || verification.Fingerprint == "" {
+		t.Errorf("expected the digest and fingerprint to be reported, got %+v", verification)
+	}
+
+	spec := &ChartSpec{ReleaseName: "signed", ChartName: signedArchive, Namespace: "default", Verify: true, Keyring: keyring}
+	if _, err := c.InstallChart(ctx, spec, nil); err != nil {
+		t.Errorf("expected the signed chart to be installed: %v", err)
+	}
+
+	helmChart, err := loader.LoadDir(chartPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unsignedArchive, err := chartutil.Save(helmChart, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.VerifyChart(unsignedArchive, keyring); !errors.Is(err, ErrChartVerificationFailed) {
+		t.Errorf("expected an unsigned chart to fail verification, got %v", err)
+	}
+
+	spec = &ChartSpec{ReleaseName: "unsigned", ChartName: unsignedArchive, Namespace: "default", Verify: true, Keyring: keyring}
+	if _, err := c.InstallChart(ctx, spec, nil); err == nil {
+		t.Error("expected the unsigned chart to be refused")
+	}
+
+	tampered, err := os.OpenFile(signedArchive, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = tampered.Write([]byte("tampered"))
+	_ = tampered.Close()
+
+	if _, err := c.VerifyChart(signedArchive, keyring); !errors.Is(err, ErrChartVerificationFailed) {
+		t.Errorf("expected a tampered chart to fail verification, got %v", err)
+	}
+}